@@ -6,8 +6,12 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/Gerrit91/darts-counter/pkg/cli"
 	"github.com/Gerrit91/darts-counter/pkg/config"
 	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/remote"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+	"github.com/Gerrit91/darts-counter/pkg/server"
 	mainmenu "github.com/Gerrit91/darts-counter/pkg/views/main-menu"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,6 +33,14 @@ func main() {
 		defer fileCloser()
 	}
 
+	if len(os.Args) > 1 {
+		if err := cli.Run(log, config, os.Stdout, os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(config, log); err != nil {
 		log.Error("error running darts-counter", "error", err)
 		os.Exit(1)
@@ -65,16 +77,33 @@ func run(config *config.Config, log *slog.Logger) error {
 
 	log.Info("datastore initialized", "db-path", config.Database.Path)
 
-	log.Info("launching main menu")
-
 	var (
-		m = mainmenu.New(log, config, ds)
-		p = tea.NewProgram(m,
+		board = scoreboard.NewBroadcaster(log)
+		m     = mainmenu.New(log, config, ds, board)
+		p     = tea.NewProgram(m,
 			tea.WithAltScreen(),
 			tea.WithMouseCellMotion(),
 		)
 	)
 
+	if config.Server.Enabled {
+		var input *remote.Input
+		if config.Server.RemoteInput {
+			input = remote.NewInput(log, p.Send)
+			m.SetPairingCode(input.PairingCode())
+		}
+
+		srv := server.New(log, ds, board, input)
+
+		err = srv.Start(config.Server.Address)
+		if err != nil {
+			return err
+		}
+		defer srv.Close()
+	}
+
+	log.Info("launching main menu")
+
 	if _, err := p.Run(); err != nil {
 		return err
 	}
@@ -0,0 +1,170 @@
+package checkout
+
+import (
+	"math"
+	"slices"
+)
+
+// Ring is a scoring area of the dartboard, ordered from the centre outwards
+type Ring string
+
+const (
+	RingInnerBull   Ring = "inner-bull"
+	RingOuterBull   Ring = "outer-bull"
+	RingInnerSingle Ring = "inner-single"
+	RingTriple      Ring = "triple"
+	RingOuterSingle Ring = "outer-single"
+	RingDouble      Ring = "double"
+	RingOffBoard    Ring = "off-board"
+)
+
+type (
+	// Segment is a field on the dartboard, identified by its number and ring
+	Segment struct {
+		Number int
+		Ring   Ring
+	}
+
+	ringRadius struct {
+		ring  Ring
+		outer float64
+	}
+)
+
+var (
+	// clockwise order of the numbers on a dartboard, starting with 20 at the top
+	boardOrder = []int{20, 1, 18, 4, 13, 6, 10, 15, 2, 17, 3, 19, 7, 16, 8, 11, 14, 9, 12, 5}
+
+	// outer radii of the rings in millimetres as regulated for steel tip boards
+	ringRadii = []ringRadius{
+		{ring: RingInnerBull, outer: 6.35},
+		{ring: RingOuterBull, outer: 15.9},
+		{ring: RingInnerSingle, outer: 99},
+		{ring: RingTriple, outer: 107},
+		{ring: RingOuterSingle, outer: 162},
+		{ring: RingDouble, outer: 170},
+	}
+)
+
+// BoardOrder returns the numbers of the dartboard in clockwise order, starting with 20 at the top
+func BoardOrder() []int {
+	return slices.Clone(boardOrder)
+}
+
+// Neighbours returns the numbers left (counter-clockwise) and right (clockwise) of the given number,
+// zero for numbers that are not on the board
+func Neighbours(number int) (int, int) {
+	idx := slices.Index(boardOrder, number)
+	if idx < 0 {
+		return 0, 0
+	}
+
+	return boardOrder[(idx+len(boardOrder)-1)%len(boardOrder)], boardOrder[(idx+1)%len(boardOrder)]
+}
+
+// Rings returns the scoring rings from the centre outwards
+func Rings() []Ring {
+	var rs []Ring
+
+	for _, r := range ringRadii {
+		rs = append(rs, r.ring)
+	}
+
+	return rs
+}
+
+// Radius returns the inner and outer radius of a ring in millimetres
+func (r Ring) Radius() (float64, float64) {
+	inner := 0.0
+
+	for _, rr := range ringRadii {
+		if rr.ring == r {
+			return inner, rr.outer
+		}
+
+		inner = rr.outer
+	}
+
+	return inner, math.Inf(1)
+}
+
+// SegmentOf returns the segment of a score, singles are located in the outer single ring
+func SegmentOf(s *Score) Segment {
+	if s.IsMiss() {
+		return Segment{Ring: RingOffBoard}
+	}
+
+	number := s.Value() / s.GetMultiplier().Value()
+
+	if number == BullsEye {
+		if s.GetMultiplier() == Double {
+			return Segment{Number: BullsEye, Ring: RingInnerBull}
+		}
+		return Segment{Number: BullsEye, Ring: RingOuterBull}
+	}
+
+	switch s.GetMultiplier() {
+	case Double:
+		return Segment{Number: number, Ring: RingDouble}
+	case Triple:
+		return Segment{Number: number, Ring: RingTriple}
+	default:
+		return Segment{Number: number, Ring: RingOuterSingle}
+	}
+}
+
+// SegmentAt returns the segment at the given polar coordinates, the radius in millimetres from the
+// centre of the board and the angle in degrees clockwise from the top (the centre of the 20)
+func SegmentAt(radius, angle float64) Segment {
+	ring := RingOffBoard
+	for _, rr := range ringRadii {
+		if radius <= rr.outer {
+			ring = rr.ring
+			break
+		}
+	}
+
+	switch ring {
+	case RingOffBoard:
+		return Segment{Ring: RingOffBoard}
+	case RingInnerBull, RingOuterBull:
+		return Segment{Number: BullsEye, Ring: ring}
+	}
+
+	// every segment spans 18 degrees, the 20 is centred at the top
+	normalized := math.Mod(math.Mod(angle+9, 360)+360, 360)
+
+	return Segment{Number: boardOrder[int(normalized/18)%len(boardOrder)], Ring: ring}
+}
+
+// Angle returns the angle in degrees clockwise from the top at the centre of the segment's number
+func (s Segment) Angle() float64 {
+	return float64(slices.Index(boardOrder, s.Number)) * 18
+}
+
+// Neighbours returns the adjacent segments within the same ring, the bull has no neighbours
+func (s Segment) Neighbours() []Segment {
+	left, right := Neighbours(s.Number)
+	if left == 0 {
+		return nil
+	}
+
+	return []Segment{
+		{Number: left, Ring: s.Ring},
+		{Number: right, Ring: s.Ring},
+	}
+}
+
+// Score returns the score of the segment
+func (s Segment) Score() *Score {
+	switch s.Ring {
+	case RingOffBoard:
+		return NewMiss()
+	case RingInnerBull, RingDouble:
+		return NewScore(s.Number).WithMultiplier(Double)
+	case RingTriple:
+		return NewScore(s.Number).WithMultiplier(Triple)
+	default:
+		return NewScore(s.Number)
+	}
+}
@@ -0,0 +1,106 @@
+package checkout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeighbours(t *testing.T) {
+	tests := []struct {
+		number    int
+		wantLeft  int
+		wantRight int
+	}{
+		{number: 20, wantLeft: 5, wantRight: 1},
+		{number: 5, wantLeft: 12, wantRight: 20},
+		{number: 16, wantLeft: 7, wantRight: 8},
+		{number: BullsEye, wantLeft: 0, wantRight: 0},
+	}
+	for _, tt := range tests {
+		left, right := Neighbours(tt.number)
+		assert.Equal(t, tt.wantLeft, left, "left of %d", tt.number)
+		assert.Equal(t, tt.wantRight, right, "right of %d", tt.number)
+	}
+}
+
+func TestSegmentAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		radius float64
+		angle  float64
+		want   string
+	}{
+		{name: "centre", radius: 0, angle: 0, want: "DB"},
+		{name: "outer bull", radius: 10, angle: 123, want: "B"},
+		{name: "treble 20", radius: 103, angle: 0, want: "T20"},
+		{name: "just left of the 20", radius: 50, angle: -10, want: "5"},
+		{name: "double 1", radius: 166, angle: 18, want: "D1"},
+		{name: "full turn", radius: 130, angle: 360 + 18*2, want: "18"},
+		{name: "off board", radius: 200, angle: 0, want: "M"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SegmentAt(tt.radius, tt.angle).Score().String())
+		})
+	}
+}
+
+func TestSegmentOf(t *testing.T) {
+	for _, s := range []*Score{
+		NewScore(20),
+		NewScore(3).WithMultiplier(Double),
+		NewScore(19).WithMultiplier(Triple),
+		NewScore(BullsEye),
+		NewScore(BullsEye).WithMultiplier(Double),
+		NewMiss(),
+	} {
+		segment := SegmentOf(s)
+		assert.Equal(t, s.String(), segment.Score().String())
+
+		if segment.Ring == RingOffBoard || segment.Number == BullsEye {
+			continue
+		}
+
+		inner, outer := segment.Ring.Radius()
+		assert.Equal(t, segment, SegmentAt((inner+outer)/2, segment.Angle()), s.String())
+	}
+}
+
+func Test_ForNeighbourAware(t *testing.T) {
+	tests := []struct {
+		name  string
+		score int
+		limit int
+		out   CheckoutType
+		want  string
+	}{
+		{
+			name:  "single checkout stays first",
+			score: 40,
+			limit: 1,
+			out:   CheckoutTypeDoubleOut,
+			want:  "D20",
+		},
+		{
+			name:  "prefers a double where a miss still leaves a double",
+			score: 60,
+			limit: 1,
+			out:   CheckoutTypeDoubleOut,
+			want:  "20 → D20",
+		},
+		{
+			name:  "negative limit",
+			score: 60,
+			limit: -1,
+			out:   CheckoutTypeDoubleOut,
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := For(tt.score, NewCalcLimitOption(tt.limit), NewCheckoutTypeOption(tt.out), NewNeighbourAwareOption())
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
@@ -13,7 +13,9 @@ type calculator struct {
 	limit     int
 	maxThrows int
 
-	out CheckoutType
+	out            CheckoutType
+	model          *HitModel
+	neighbourAware bool
 }
 
 func newCalculator(opts ...option) (*calculator, error) {
@@ -32,6 +34,10 @@ func newCalculator(opts ...option) (*calculator, error) {
 			c.maxThrows = opt.max
 		case *optionCheckoutType:
 			c.out = opt.out
+		case *optionHitModel:
+			c.model = opt.model
+		case *optionNeighbourAware:
+			c.neighbourAware = true
 		default:
 			return nil, fmt.Errorf("unknown option: %T", opt)
 		}
@@ -76,7 +82,7 @@ func (c *calculator) recurse(remaining, throw int) {
 	}
 
 	// look for immediate triple-out wins
-	if c.out == CheckoutTypeStraightOut && remaining <= 60 {
+	if (c.out == CheckoutTypeStraightOut || c.out == CheckoutTypeMasterOut) && remaining <= 60 {
 		for _, single := range Singles() {
 			if single.Value() == BullsEye {
 				// there is no triple bullseye
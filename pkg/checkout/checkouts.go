@@ -1,6 +1,7 @@
 package checkout
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 )
@@ -8,9 +9,11 @@ import (
 const (
 	CheckoutTypeStraightOut CheckoutType = "straight-out"
 	CheckoutTypeDoubleOut   CheckoutType = "double-out"
+	CheckoutTypeMasterOut   CheckoutType = "master-out"
 
 	CheckinTypeStraightIn CheckinType = "straight-in"
 	CheckinTypeDoubleIn   CheckinType = "double-in"
+	CheckinTypeMasterIn   CheckinType = "master-in"
 )
 
 type (
@@ -18,21 +21,137 @@ type (
 	CheckinType  string
 
 	Checkout struct {
-		scores []*Score
+		scores      []*Score
+		probability float64
 	}
 
 	Checkouts []*Checkout
 )
 
+// ParseCheckoutType parses a check-out type, the suffix may be omitted (e.g. "double")
+func ParseCheckoutType(out string) (CheckoutType, error) {
+	switch t := CheckoutType(strings.TrimSuffix(out, "-out") + "-out"); t {
+	case CheckoutTypeStraightOut, CheckoutTypeDoubleOut, CheckoutTypeMasterOut:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown check-out type: %s", out)
+	}
+}
+
 func For(score int, opts ...option) Checkouts {
 	s, err := newCalculator(opts...)
 	if err != nil {
 		panic(err) // TODO: too harsh
 	}
 
+	if s.model != nil {
+		return forProbability(score, s)
+	}
+
+	if s.neighbourAware {
+		return forNeighbourAware(score, s)
+	}
+
 	return forThrow(score, 1, s)
 }
 
+// forProbability ranks all possible checkouts by the probability to finish, which also
+// takes into account what is left after a missed dart
+func forProbability(remaining int, c *calculator) Checkouts {
+	if remaining <= 0 || remaining > 180 {
+		return nil
+	}
+
+	cs := newSolver(c.model, c.out).routes(remaining, c.maxThrows)
+	if limit := max(c.limit, 0); len(cs) > limit {
+		cs = cs[:limit]
+	}
+
+	return cs
+}
+
+// forNeighbourAware calculates a broader range of checkouts and prefers those where darts that land
+// in a neighbouring segment of the board still leave a finish
+func forNeighbourAware(remaining int, c *calculator) Checkouts {
+	const candidates = 20
+
+	var opts []option
+	for _, o := range c.opts {
+		switch o.(type) {
+		case *optionCalcLimit, *optionNeighbourAware:
+			continue
+		default:
+			opts = append(opts, o)
+		}
+	}
+
+	// the options were already validated when the calculator was created
+	limit := max(candidates, c.limit)
+	candidateCalculator := &calculator{
+		opts:      append(opts, NewCalcLimitOption(limit)),
+		limit:     limit,
+		maxThrows: c.maxThrows,
+		out:       c.out,
+	}
+
+	var (
+		cs        = forThrow(remaining, 1, candidateCalculator)
+		tolerance = map[*Checkout]int{}
+	)
+
+	for _, cs := range cs {
+		tolerance[cs] = c.missTolerance(cs, remaining)
+	}
+
+	// short checkouts are still preferred, the tolerance decides between checkouts of the same length
+	sort.SliceStable(cs, func(i, j int) bool {
+		if len(cs[i].scores) != len(cs[j].scores) {
+			return len(cs[i].scores) < len(cs[j].scores)
+		}
+		return tolerance[cs[i]] > tolerance[cs[j]]
+	})
+
+	if limit := max(c.limit, 0); len(cs) > limit {
+		cs = cs[:limit]
+	}
+
+	return cs
+}
+
+// missTolerance counts the darts landing in a neighbouring segment of a planned dart that still leave a finish
+func (c *calculator) missTolerance(cs *Checkout, remaining int) int {
+	var (
+		count int
+		rest  = remaining
+	)
+
+	for i, planned := range cs.scores {
+		dartsLeft := c.maxThrows - i - 1
+
+		for _, neighbour := range SegmentOf(planned).Neighbours() {
+			var (
+				landed = neighbour.Score()
+				left   = rest - landed.Value()
+			)
+
+			switch {
+			case left == 0:
+				if c.out.IsFinish(landed) {
+					count++
+				}
+			case left > 0 && dartsLeft > 0:
+				if len(For(left, NewCalcLimitOption(1), NewMaxThrowsOption(dartsLeft), NewCheckoutTypeOption(c.out))) > 0 {
+					count++
+				}
+			}
+		}
+
+		rest -= planned.Value()
+	}
+
+	return count
+}
+
 func forThrow(remaining, throw int, c *calculator) Checkouts {
 	c.recurse(remaining, throw)
 
@@ -56,7 +175,7 @@ func (c *Checkout) orderScores(out CheckoutType) {
 			return c.scores[j].Value() < c.scores[i].Value()
 		})
 
-	case CheckoutTypeDoubleOut:
+	case CheckoutTypeDoubleOut, CheckoutTypeMasterOut:
 		withoutLast := c.scores[:len(c.scores)-1]
 
 		sort.Slice(withoutLast, func(i, j int) bool {
@@ -69,6 +188,45 @@ func (c *Checkout) orderScores(out CheckoutType) {
 	}
 }
 
+// IsFinish returns whether the given score is allowed as the last dart of a checkout
+func (t CheckoutType) IsFinish(s *Score) bool {
+	switch t {
+	case CheckoutTypeDoubleOut:
+		return s.GetMultiplier() == Double
+	case CheckoutTypeMasterOut:
+		return s.GetMultiplier() == Double || s.GetMultiplier() == Triple
+	default:
+		return !s.IsMiss()
+	}
+}
+
+// LeavesOneBust returns whether a remaining score of 1 is a bust, because it cannot be finished anymore
+func (t CheckoutType) LeavesOneBust() bool {
+	return t == CheckoutTypeDoubleOut || t == CheckoutTypeMasterOut
+}
+
+// IsCheckin returns whether the given score is allowed to start scoring
+func (t CheckinType) IsCheckin(s *Score) bool {
+	switch t {
+	case CheckinTypeDoubleIn:
+		return s.GetMultiplier() == Double
+	case CheckinTypeMasterIn:
+		return s.GetMultiplier() == Double || s.GetMultiplier() == Triple
+	default:
+		return !s.IsMiss()
+	}
+}
+
+// GetProbability returns the probability to finish with this checkout, only calculated when a hit model is given
+func (c *Checkout) GetProbability() float64 {
+	return c.probability
+}
+
+// GetScores returns the darts of the checkout in the order they should be thrown
+func (c *Checkout) GetScores() []*Score {
+	return append([]*Score{}, c.scores...)
+}
+
 func (c *Checkout) String() string {
 	var scores []string
 	for _, s := range c.scores {
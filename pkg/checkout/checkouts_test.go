@@ -171,3 +171,27 @@ func Test_For(t *testing.T) {
 		})
 	}
 }
+
+func Test_ForMasterOut(t *testing.T) {
+	tests := []struct {
+		score int
+		limit int
+		want  string
+	}{
+		{score: 1, limit: 1, want: ""},
+		{score: 2, limit: 1, want: "D1"},
+		{score: 3, limit: 2, want: "T1, 1 → D1"},
+		{score: 21, limit: 2, want: "T7, 1 → D10"},
+		{score: 60, limit: 2, want: "T20, 3 → T19"},
+		{score: 120, limit: 2, want: "T20 → T20, DB → B → T15"},
+		{score: 179, limit: 1, want: ""},
+		{score: 180, limit: 1, want: "T20 → T20 → T20"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("score_%d_%s_limit_%d", tt.score, CheckoutTypeMasterOut, tt.limit), func(t *testing.T) {
+			if got := For(tt.score, NewCalcLimitOption(tt.limit), NewCheckoutTypeOption(CheckoutTypeMasterOut)); got.String() != tt.want {
+				t.Errorf("%v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,10 @@ type (
 	optionCheckoutType struct {
 		out CheckoutType
 	}
+	optionHitModel struct {
+		model *HitModel
+	}
+	optionNeighbourAware struct{}
 )
 
 // NewCalcLimitOption stops the checkouts calculation after limit of results was reached
@@ -28,3 +32,13 @@ func NewMaxThrowsOption(max int) *optionMaxThrows {
 func NewCheckoutTypeOption(out CheckoutType) *optionCheckoutType {
 	return &optionCheckoutType{out: out}
 }
+
+// NewHitModelOption ranks the checkouts by the probability to finish for a player with the given hit model
+func NewHitModelOption(model *HitModel) *optionHitModel {
+	return &optionHitModel{model: model}
+}
+
+// NewNeighbourAwareOption prefers checkouts where a dart that lands in a neighbouring segment still leaves a finish
+func NewNeighbourAwareOption() *optionNeighbourAware {
+	return &optionNeighbourAware{}
+}
@@ -0,0 +1,263 @@
+package checkout
+
+import (
+	"slices"
+	"sort"
+)
+
+type (
+	// HitModel describes how likely a player hits an aimed target and where a missed dart lands
+	HitModel struct {
+		// Accuracy is the probability to hit a specific target in its notation, e.g. "T20" or "DB",
+		// targets that are not contained fall back to the ring accuracy
+		Accuracy map[string]float64
+		// RingAccuracy is the probability to hit a target with the given multiplier
+		RingAccuracy map[Multiplier]float64
+		// BullAccuracy is the probability to hit the single bull
+		BullAccuracy float64
+		// DoubleBullAccuracy is the probability to hit the double bull
+		DoubleBullAccuracy float64
+		// Scatter is the share of missed darts that land in the neighbouring segments
+		Scatter float64
+	}
+
+	// Outcome is a field that a dart lands in with the given probability
+	Outcome struct {
+		Score       *Score
+		Probability float64
+	}
+
+	solver struct {
+		model *HitModel
+		out   CheckoutType
+		aims  []*Score
+		memo  map[[2]int]float64
+	}
+)
+
+// DefaultHitModel returns a hit model of an average club player
+func DefaultHitModel() *HitModel {
+	return &HitModel{
+		RingAccuracy: map[Multiplier]float64{
+			None:   0.85,
+			Double: 0.35,
+			Triple: 0.25,
+		},
+		BullAccuracy:       0.4,
+		DoubleBullAccuracy: 0.15,
+		Scatter:            0.8,
+	}
+}
+
+// Outcomes returns where a dart that is aimed at the given target lands:
+//   - singles are either hit or missed into the neighbouring singles, otherwise the dart misses the board
+//   - triples are either hit, missed into the neighbouring singles or into the single of the same number
+//   - doubles are either hit, missed into the neighbouring singles or fall into the single of the same number or off the board
+//   - the bull is either hit or missed into any single, the double bull falls into the single bull or any single
+func (m *HitModel) Outcomes(aim *Score) []Outcome {
+	var (
+		hit     = m.hitProbability(aim)
+		miss    = 1 - hit
+		number  = aim.Value() / aim.GetMultiplier().Value()
+		scatter = miss * m.Scatter
+		rest    = miss - scatter
+
+		outcomes = []Outcome{{Score: aim, Probability: hit}}
+	)
+
+	if number == BullsEye {
+		if aim.GetMultiplier() == Double {
+			outcomes = append(outcomes, Outcome{Score: NewScore(BullsEye), Probability: rest})
+		} else {
+			scatter = miss
+		}
+
+		for _, n := range boardOrder {
+			outcomes = append(outcomes, Outcome{Score: NewScore(n), Probability: scatter / float64(len(boardOrder))})
+		}
+
+		return outcomes
+	}
+
+	left, right := Neighbours(number)
+	outcomes = append(outcomes,
+		Outcome{Score: NewScore(left), Probability: scatter / 2},
+		Outcome{Score: NewScore(right), Probability: scatter / 2},
+	)
+
+	switch aim.GetMultiplier() {
+	case Triple:
+		outcomes = append(outcomes, Outcome{Score: NewScore(number), Probability: rest})
+	case Double:
+		outcomes = append(outcomes,
+			Outcome{Score: NewScore(number), Probability: rest / 2},
+			Outcome{Score: NewMiss(), Probability: rest / 2},
+		)
+	default:
+		outcomes = append(outcomes, Outcome{Score: NewMiss(), Probability: rest})
+	}
+
+	return outcomes
+}
+
+func (m *HitModel) hitProbability(aim *Score) float64 {
+	if p, ok := m.Accuracy[aim.String()]; ok {
+		return p
+	}
+
+	if aim.Value()/aim.GetMultiplier().Value() == BullsEye {
+		if aim.GetMultiplier() == Double {
+			return m.DoubleBullAccuracy
+		}
+		return m.BullAccuracy
+	}
+
+	return m.RingAccuracy[aim.GetMultiplier()]
+}
+
+func newSolver(model *HitModel, out CheckoutType) *solver {
+	var aims []*Score
+
+	for _, m := range []Multiplier{None, Double, Triple} {
+		for _, single := range Singles() {
+			if m == Triple && single.Value() == BullsEye {
+				continue
+			}
+
+			aims = append(aims, NewScore(single.Value()).WithMultiplier(m))
+		}
+	}
+
+	return &solver{
+		model: model,
+		out:   out,
+		aims:  aims,
+		memo:  map[[2]int]float64{},
+	}
+}
+
+// best returns the probability to finish the remaining score with the given darts when always
+// aiming at the most promising target
+func (s *solver) best(remaining, darts int) float64 {
+	if darts <= 0 || remaining <= 0 {
+		return 0
+	}
+
+	key := [2]int{remaining, darts}
+	if p, ok := s.memo[key]; ok {
+		return p
+	}
+
+	best := 0.0
+	for _, aim := range s.aims {
+		if aim.Value() > remaining {
+			continue
+		}
+
+		if p := s.aimAt(aim, remaining, darts); p > best {
+			best = p
+		}
+	}
+
+	s.memo[key] = best
+
+	return best
+}
+
+func (s *solver) aimAt(aim *Score, remaining, darts int) float64 {
+	p := 0.0
+
+	for _, o := range s.model.Outcomes(aim) {
+		p += o.Probability * s.continueWith(o.Score, remaining, darts, func(rest, darts int) float64 {
+			return s.best(rest, darts)
+		})
+	}
+
+	return p
+}
+
+// route returns the probability to finish when following the checkout route as long as the darts land as planned
+// and continuing with the most promising targets after a dart went astray
+func (s *solver) route(scores []*Score, remaining, darts int) float64 {
+	if len(scores) == 0 || darts <= 0 {
+		return s.best(remaining, darts)
+	}
+
+	var (
+		aim = scores[0]
+		p   = 0.0
+	)
+
+	for _, o := range s.model.Outcomes(aim) {
+		p += o.Probability * s.continueWith(o.Score, remaining, darts, func(rest, darts int) float64 {
+			if o.Score.String() == aim.String() {
+				return s.route(scores[1:], rest, darts)
+			}
+			return s.best(rest, darts)
+		})
+	}
+
+	return p
+}
+
+func (s *solver) continueWith(landed *Score, remaining, darts int, next func(rest, darts int) float64) float64 {
+	rest := remaining - landed.Value()
+
+	switch {
+	case rest == 0 && s.isFinish(landed):
+		return 1
+	case rest <= 0:
+		return 0 // bust
+	case rest == 1 && s.out.LeavesOneBust():
+		return 0 // bust
+	default:
+		return next(rest, darts-1)
+	}
+}
+
+func (s *solver) isFinish(landed *Score) bool {
+	return s.out.IsFinish(landed)
+}
+
+// routes returns all checkout routes for the remaining score with the given amount of darts
+// ordered by their probability to finish, the most promising first
+func (s *solver) routes(remaining, darts int) Checkouts {
+	var (
+		cs      Checkouts
+		collect func(planned []*Score, rest, darts int)
+	)
+
+	collect = func(planned []*Score, rest, darts int) {
+		if darts <= 0 {
+			return
+		}
+
+		for _, aim := range s.aims {
+			left := rest - aim.Value()
+
+			switch {
+			case left == 0 && s.isFinish(aim):
+				cs = append(cs, checkout(append(slices.Clone(planned), aim)...))
+			case left <= 0, left == 1 && s.out.LeavesOneBust():
+				continue
+			default:
+				collect(append(slices.Clone(planned), aim), left, darts-1)
+			}
+		}
+	}
+
+	collect(nil, remaining, darts)
+
+	for _, c := range cs {
+		c.probability = s.route(c.scores, remaining, darts)
+	}
+
+	sort.SliceStable(cs, func(i, j int) bool {
+		if cs[i].probability == cs[j].probability {
+			return len(cs[i].scores) < len(cs[j].scores)
+		}
+		return cs[i].probability > cs[j].probability
+	})
+
+	return cs
+}
@@ -0,0 +1,107 @@
+package checkout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ForHitModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		score     int
+		limit     int
+		out       CheckoutType
+		model     *HitModel
+		want      string
+		wantFirst float64
+	}{
+		{
+			name:  "perfect player prefers the shortest checkout",
+			score: 40,
+			limit: 1,
+			out:   CheckoutTypeDoubleOut,
+			model: &HitModel{
+				RingAccuracy:       map[Multiplier]float64{None: 1, Double: 1, Triple: 1},
+				BullAccuracy:       1,
+				DoubleBullAccuracy: 1,
+			},
+			want:      "D20",
+			wantFirst: 1,
+		},
+		{
+			name:  "favourite double is preferred",
+			score: 40,
+			limit: 1,
+			out:   CheckoutTypeDoubleOut,
+			model: &HitModel{
+				Accuracy:     map[string]float64{"D16": 0.9},
+				RingAccuracy: map[Multiplier]float64{None: 0.9, Double: 0.2, Triple: 0.1},
+				Scatter:      0.8,
+			},
+			want: "8 → D16",
+		},
+		{
+			name:  "no checkout possible",
+			score: 179,
+			limit: 1,
+			out:   CheckoutTypeDoubleOut,
+			model: DefaultHitModel(),
+			want:  "",
+		},
+		{
+			name:  "straight-out with bad triples avoids the triple",
+			score: 60,
+			limit: 1,
+			out:   CheckoutTypeStraightOut,
+			model: &HitModel{
+				RingAccuracy: map[Multiplier]float64{None: 0.95, Double: 0.3, Triple: 0.05},
+				Scatter:      0.8,
+			},
+			want: "20 → 20 → 20",
+		},
+		{
+			name:  "negative limit",
+			score: 60,
+			limit: -1,
+			out:   CheckoutTypeDoubleOut,
+			model: DefaultHitModel(),
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := For(tt.score, NewCalcLimitOption(tt.limit), NewCheckoutTypeOption(tt.out), NewHitModelOption(tt.model))
+			assert.Equal(t, tt.want, got.String())
+
+			if tt.wantFirst > 0 {
+				require.NotEmpty(t, got)
+				assert.InDelta(t, tt.wantFirst, got[0].GetProbability(), 0.0001)
+			}
+
+			for i := 1; i < len(got); i++ {
+				assert.GreaterOrEqual(t, got[i-1].GetProbability(), got[i].GetProbability())
+			}
+		})
+	}
+}
+
+func TestHitModel_Outcomes(t *testing.T) {
+	m := DefaultHitModel()
+
+	for _, aim := range []*Score{
+		NewScore(20),
+		NewScore(20).WithMultiplier(Double),
+		NewScore(20).WithMultiplier(Triple),
+		NewScore(BullsEye),
+		NewScore(BullsEye).WithMultiplier(Double),
+	} {
+		total := 0.0
+		for _, o := range m.Outcomes(aim) {
+			total += o.Probability
+		}
+
+		assert.InDelta(t, 1, total, 0.0001, aim.String())
+	}
+}
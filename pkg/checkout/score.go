@@ -25,6 +25,11 @@ func NewScore(score int) *Score {
 	}
 }
 
+// NewMiss returns the score of a dart that did not hit any field
+func NewMiss() *Score {
+	return NewScore(0)
+}
+
 func ParseScore(input string) (*Score, error) {
 	var (
 		multiplier Multiplier
@@ -32,10 +37,15 @@ func ParseScore(input string) (*Score, error) {
 		triple   = strings.ToLower(string(Triple))
 		double   = strings.ToLower(string(Double))
 		bullseye = strings.ToLower("B")
+		miss     = strings.ToLower("M")
 	)
 
 	input = strings.ToLower(input)
 
+	if input == miss {
+		return NewMiss(), nil
+	}
+
 	if strings.HasPrefix(input, triple) {
 		multiplier = Triple
 		input = strings.TrimPrefix(input, triple)
@@ -83,6 +93,10 @@ func (s *Score) Value() int {
 	return value
 }
 
+func (s *Score) IsMiss() bool {
+	return s.score == 0
+}
+
 func (s *Score) GetMultiplier() Multiplier {
 	return s.multiplier
 }
@@ -90,8 +104,11 @@ func (s *Score) GetMultiplier() Multiplier {
 func (s *Score) String() string {
 	representation := strconv.Itoa(s.score)
 
-	if s.score == BullsEye {
+	switch s.score {
+	case BullsEye:
 		representation = "B"
+	case 0:
+		representation = "M"
 	}
 
 	return string(s.multiplier) + representation
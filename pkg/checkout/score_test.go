@@ -66,6 +66,16 @@ func TestParseScore(t *testing.T) {
 			input:   "21",
 			wantErr: fmt.Errorf("score must be between 1 and 20 (or B for bullseye)"),
 		},
+		{
+			name:  "miss",
+			input: "M",
+			want:  checkout.NewMiss(),
+		},
+		{
+			name:    "disallow multiplier on miss",
+			input:   "DM",
+			wantErr: &strconv.NumError{Func: "Atoi", Num: "m", Err: fmt.Errorf("invalid syntax")},
+		},
 		{
 			name:    "disallow triple bullseye",
 			input:   "TB",
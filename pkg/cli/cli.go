@@ -0,0 +1,537 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/multiplayer"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+	"github.com/Gerrit91/darts-counter/pkg/server"
+)
+
+const usage = `usage: darts-counter [command]
+
+without a command, the interactive main menu is launched.
+
+commands:
+  checkout <score> [--out straight|double|master] [--limit n] [--darts n]   print checkout variants for a score
+  stats players [--json] [--filter query]                                   print the statistics of all players
+  stats rebuild                                                             recompute the stored statistics of all players
+  games list [--json] [--filter query]                                      print all recorded games
+  games export [--format json|csv] [--output file] [--filter query]         export games as json archive or csv with one row per move
+  games import <file>                                                       import the games of a json archive, known games are skipped
+  serve [--address host:port]                                               serve the read-only http api until interrupted
+  ssh [--address host:port]                                                 serve a shared game over ssh until interrupted, players are identified by their authorized key
+
+filter queries consist of key:value pairs, e.g. "player:Alice type:501 days:30".
+supported keys: player, type, out, in, days, from, to (YYYY-MM-DD), finished (yes|no)`
+
+type (
+	cli struct {
+		log *slog.Logger
+		c   *config.Config
+		out io.Writer
+	}
+)
+
+// Run executes a headless subcommand given by args (without the program name), the output is written to out
+func Run(log *slog.Logger, c *config.Config, out io.Writer, args []string) error {
+	cli := &cli{
+		log: log,
+		c:   c,
+		out: out,
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("no command given\n\n%s", usage)
+	}
+
+	switch args[0] {
+	case "checkout":
+		return cli.checkout(args[1:])
+	case "stats":
+		if len(args) < 2 {
+			return fmt.Errorf("unknown stats command, expected: stats players|rebuild\n\n%s", usage)
+		}
+		switch args[1] {
+		case "players":
+			return cli.playerStats(args[2:])
+		case "rebuild":
+			return cli.rebuildStats(args[2:])
+		default:
+			return fmt.Errorf("unknown stats command, expected: stats players|rebuild\n\n%s", usage)
+		}
+	case "games":
+		if len(args) < 2 {
+			return fmt.Errorf("unknown games command, expected: games list|export|import\n\n%s", usage)
+		}
+		switch args[1] {
+		case "list":
+			return cli.listGames(args[2:])
+		case "export":
+			return cli.exportGames(args[2:])
+		case "import":
+			return cli.importGames(args[2:])
+		default:
+			return fmt.Errorf("unknown games command, expected: games list|export|import\n\n%s", usage)
+		}
+	case "serve":
+		return cli.serve(args[1:])
+	case "ssh":
+		return cli.serveSSH(args[1:])
+	case "help", "-h", "--help":
+		_, err := fmt.Fprintln(cli.out, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], usage)
+	}
+}
+
+func (c *cli) checkout(args []string) error {
+	var (
+		fs    = newFlagSet("checkout")
+		out   = fs.String("out", "double", "the check-out type (straight, double or master)")
+		limit = fs.Int("limit", 1, "the maximum amount of checkout variants")
+		darts = fs.Int("darts", 3, "the amount of darts left in the turn")
+	)
+
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one score, e.g. checkout 121")
+	}
+
+	score, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return fmt.Errorf("unable to parse score: %w", err)
+	}
+
+	checkoutType, err := checkout.ParseCheckoutType(*out)
+	if err != nil {
+		return err
+	}
+
+	checkouts := checkout.For(score,
+		checkout.NewCalcLimitOption(*limit),
+		checkout.NewCheckoutTypeOption(checkoutType),
+		checkout.NewMaxThrowsOption(*darts),
+	)
+
+	if len(checkouts) == 0 {
+		_, err = fmt.Fprintf(c.out, "no checkout possible for %d (%s)\n", score, checkoutType)
+		return err
+	}
+
+	for _, cs := range checkouts {
+		if _, err := fmt.Fprintln(c.out, cs.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *cli) playerStats(args []string) error {
+	var (
+		fs     = newFlagSet("stats players")
+		asJSON = fs.Bool("json", false, "print as json")
+		query  = fs.String("filter", "", "only take games matching the filter query into account")
+	)
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	ds, err := datastore.New(c.log, c.c.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	var stats []*datastore.PlayerStats
+
+	if *query == "" {
+		stats, err = ds.ListPlayerStats()
+		if err != nil {
+			return err
+		}
+	} else {
+		profiles, err := ds.ListPlayerProfiles()
+		if err != nil {
+			return err
+		}
+
+		filters, err := datastore.ParseFilters(*query, profiles, time.Now())
+		if err != nil {
+			return err
+		}
+
+		games, err := ds.ListGameStats(filters...)
+		if err != nil {
+			return err
+		}
+
+		stats, err = datastore.ToPlayerStats(games, datastore.ToPlayerNames(profiles))
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	if *asJSON {
+		if stats == nil {
+			stats = []*datastore.PlayerStats{}
+		}
+		return c.printJSON(stats)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PLAYER\tGAMES\tWINS\t⌀-RANK\t⌀-SCORE\tHIGHEST\tBUSTS")
+	for _, ps := range stats {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%.1f\t%d\t%d\n", ps.Name, ps.GamesPlayed, ps.RanksCount[1], ps.AverageRank, ps.AverageScore, ps.HighestScore.Total, ps.Busts)
+	}
+
+	return w.Flush()
+}
+
+func (c *cli) rebuildStats(args []string) error {
+	fs := newFlagSet("stats rebuild")
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	ds, err := datastore.New(c.log, c.c.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	start := time.Now()
+
+	err = ds.RebuildPlayerStats()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.out, "rebuilt player statistics in %s\n", time.Since(start).Truncate(time.Millisecond))
+	return err
+}
+
+func (c *cli) listGames(args []string) error {
+	var (
+		fs     = newFlagSet("games list")
+		asJSON = fs.Bool("json", false, "print as json")
+		query  = fs.String("filter", "", "only take games matching the filter query into account")
+	)
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	ds, err := datastore.New(c.log, c.c.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	profiles, err := ds.ListPlayerProfiles()
+	if err != nil {
+		return err
+	}
+
+	filters, err := datastore.ParseFilters(*query, profiles, time.Now())
+	if err != nil {
+		return err
+	}
+
+	games, err := ds.ListGameStats(filters...)
+	if err != nil {
+		return err
+	}
+
+	names := datastore.ToPlayerNames(profiles)
+
+	if *asJSON {
+		if games == nil {
+			games = []*datastore.GameStats{}
+		}
+		return c.printJSON(games)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tSTART\tGAME\tDURATION\tWINNER\tPLAYERS")
+	for _, g := range games {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", g.ID, g.Start.Format(time.DateTime), g.GameName(), g.End.Sub(g.Start).Truncate(time.Second), names.Of(g.Ranks[1]), strings.Join(names.All(g.Players), ", "))
+	}
+
+	return w.Flush()
+}
+
+func (c *cli) exportGames(args []string) error {
+	var (
+		fs     = newFlagSet("games export")
+		format = fs.String("format", "json", "the export format (json or csv)")
+		output = fs.String("output", "", "the file to write to, defaults to stdout")
+		query  = fs.String("filter", "", "only export games matching the filter query")
+	)
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown export format: %s", *format)
+	}
+
+	ds, err := datastore.New(c.log, c.c.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	profiles, err := ds.ListPlayerProfiles()
+	if err != nil {
+		return err
+	}
+
+	filters, err := datastore.ParseFilters(*query, profiles, time.Now())
+	if err != nil {
+		return err
+	}
+
+	games, err := ds.ListGameStats(filters...)
+	if err != nil {
+		return err
+	}
+
+	matches, err := ds.ListMatchStats()
+	if err != nil {
+		return err
+	}
+
+	out := c.out
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("unable to create export file: %w", err)
+		}
+		defer f.Close()
+
+		out = f
+	}
+
+	if *format == "csv" {
+		err = datastore.WriteMovesCSV(out, games, datastore.ToPlayerNames(profiles))
+	} else {
+		err = datastore.WriteArchive(out, datastore.NewArchive(games, matches, profiles, time.Now()))
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		_, err = fmt.Fprintf(c.out, "exported %d games to %s\n", len(games), *output)
+	}
+
+	return err
+}
+
+func (c *cli) importGames(args []string) error {
+	fs := newFlagSet("games import")
+
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one archive file, e.g. games import darts-counter-export.json")
+	}
+
+	f, err := os.Open(positional[0])
+	if err != nil {
+		return fmt.Errorf("unable to open archive: %w", err)
+	}
+	defer f.Close()
+
+	archive, err := datastore.ReadArchive(f)
+	if err != nil {
+		return err
+	}
+
+	ds, err := datastore.New(c.log, c.c.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	result, err := ds.ImportArchive(archive)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.out, "imported %d games (%d already known), %d matches and %d new players\n", result.Games, result.Skipped, result.Matches, result.Players)
+	return err
+}
+
+func (c *cli) serve(args []string) error {
+	var (
+		fs      = newFlagSet("serve")
+		address = fs.String("address", c.c.Server.Address, "the address to listen on")
+	)
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	ds, err := datastore.New(c.log, c.c.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := server.New(c.log, ds, scoreboard.NewBroadcaster(c.log), nil)
+
+	err = srv.Start(*address)
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
+	_, err = fmt.Fprintf(c.out, "serving http api on http://%s, press ctrl+c to stop\n", *address)
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func (c *cli) printJSON(v any) error {
+	enc := json.NewEncoder(c.out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parse parses the flags of a command and returns the positional arguments, flags are allowed
+// before and after positional arguments (e.g. "checkout 121 --limit 5")
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%s: %w", fs.Name(), err)
+		}
+
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// serveSSH runs a game that is shared by all terminals connected over ssh, the http api and the scoreboard
+// are served as well if the server is enabled
+func (c *cli) serveSSH(args []string) error {
+	var (
+		fs      = newFlagSet("ssh")
+		address = fs.String("address", c.c.SSH.Address, "the address to listen on")
+	)
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	ds, err := datastore.New(c.log, c.c.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	board := scoreboard.NewBroadcaster(c.log)
+
+	session, err := multiplayer.NewSession(c.log, ds, board)
+	if err != nil {
+		return err
+	}
+
+	srv, err := multiplayer.NewServer(c.log, session, c.c.SSH)
+	if err != nil {
+		return err
+	}
+
+	err = srv.Start(*address)
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
+	if c.c.Server.Enabled {
+		api := server.New(c.log, ds, board, nil)
+
+		err = api.Start(c.c.Server.Address)
+		if err != nil {
+			return err
+		}
+		defer api.Close()
+
+		_, err = fmt.Fprintf(c.out, "serving scoreboard on http://%s/scoreboard\n", c.c.Server.Address)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(c.out, "serving shared game over ssh on %s, connect with ssh -p <port> <host>, press ctrl+c to stop\n", *address)
+	if err != nil {
+		return err
+	}
+
+	if c.c.SSH.AuthorizedKeysPath == "" {
+		_, err = fmt.Fprintln(c.out, "no authorized keys are configured, all users can only watch the game")
+		if err != nil {
+			return err
+		}
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
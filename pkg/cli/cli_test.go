@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Checkout(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "defaults to double-out",
+			args: []string{"checkout", "40"},
+			want: "D20\n",
+		},
+		{
+			name: "flags after the score",
+			args: []string{"checkout", "121", "--out", "double", "--limit", "2"},
+			want: "T20 → B → D18\nT20 → 11 → DB\n",
+		},
+		{
+			name: "flags before the score",
+			args: []string{"checkout", "--out=straight-out", "60"},
+			want: "T20\n",
+		},
+		{
+			name: "darts left",
+			args: []string{"checkout", "100", "--darts", "1"},
+			want: "no checkout possible for 100 (double-out)\n",
+		},
+		{
+			name:    "unknown checkout type",
+			args:    []string{"checkout", "40", "--out", "triple"},
+			wantErr: "unknown check-out type: triple",
+		},
+		{
+			name:    "missing score",
+			args:    []string{"checkout"},
+			wantErr: "expected exactly one score, e.g. checkout 121",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := Run(slog.New(slog.DiscardHandler), nil, &out, tt.args)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
@@ -14,11 +14,19 @@ const (
 	GameType501  GameType = "501"
 	GameType701  GameType = "701"
 	GameType1001 GameType = "1001"
+
+	// GameTypeCustom is a x01 game with a free-form start score
+	GameTypeCustom GameType = "custom"
+
+	GameTypeCricket        GameType = "cricket"
+	GameTypeAroundTheClock GameType = "around-the-clock"
 )
 
 type Config struct {
 	Database *DatabaseConfig `json:"database"`
 	Logging  *LoggingConfig  `json:"logging"`
+	Server   *ServerConfig   `json:"server"`
+	SSH      *SSHConfig      `json:"ssh"`
 }
 
 type LoggingConfig struct {
@@ -31,6 +39,24 @@ type DatabaseConfig struct {
 	Path string `json:"path"`
 }
 
+// ServerConfig configures the http api and the scoreboard, which run next to the main menu when enabled
+type ServerConfig struct {
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address"`
+	// RemoteInput allows to enter scores of the running game from other devices
+	RemoteInput bool `json:"remote_input"`
+}
+
+// SSHConfig configures the ssh server for multiplayer games, which is started with the ssh command
+type SSHConfig struct {
+	Address string `json:"address"`
+	// HostKeyPath is the path of the server's private key, a key is generated if the file does not exist
+	HostKeyPath string `json:"host_key_path"`
+	// AuthorizedKeysPath restricts the access to the keys of an authorized_keys file, the comment of a key names its player.
+	// All users are accepted as spectators if empty.
+	AuthorizedKeysPath string `json:"authorized_keys_path"`
+}
+
 func ReadConfig() (*Config, error) {
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
@@ -68,4 +94,25 @@ func (c *Config) Default() {
 			Level:   "info",
 		}
 	}
+
+	if c.Server == nil {
+		c.Server = &ServerConfig{}
+	}
+
+	if c.Server.Address == "" {
+		// only reachable from the local machine unless configured otherwise
+		c.Server.Address = "127.0.0.1:8080"
+	}
+
+	if c.SSH == nil {
+		c.SSH = &SSHConfig{}
+	}
+
+	if c.SSH.Address == "" {
+		c.SSH.Address = "127.0.0.1:2222"
+	}
+
+	if c.SSH.HostKeyPath == "" {
+		c.SSH.HostKeyPath = "darts-counter_ed25519"
+	}
 }
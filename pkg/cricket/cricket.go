@@ -0,0 +1,187 @@
+package cricket
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+)
+
+const (
+	// MarksToClose is the amount of marks a player needs on a target to close it
+	MarksToClose = 3
+)
+
+type (
+	// Board keeps track of the marks and points of all players in a cricket game
+	Board struct {
+		players []string
+		marks   map[string]map[int]int
+		points  map[string]int
+	}
+
+	// Turn is the outcome of a player's turn
+	Turn struct {
+		Points int
+		Marks  int
+	}
+)
+
+// Targets returns the numbers that are in play in a cricket game
+func Targets() []int {
+	return []int{20, 19, 18, 17, 16, 15, checkout.BullsEye}
+}
+
+func NewBoard(players []string) *Board {
+	b := &Board{
+		players: players,
+		marks:   map[string]map[int]int{},
+		points:  map[string]int{},
+	}
+
+	for _, p := range players {
+		b.marks[p] = map[int]int{}
+	}
+
+	return b
+}
+
+// Throw applies the scores of a player's turn to the board
+func (b *Board) Throw(player string, scores []*checkout.Score) (Turn, error) {
+	marks, ok := b.marks[player]
+	if !ok {
+		return Turn{}, fmt.Errorf("unknown player %q", player)
+	}
+
+	if len(scores) > 3 {
+		return Turn{}, fmt.Errorf("no more than three throws are allowed")
+	}
+
+	var turn Turn
+
+	for _, score := range scores {
+		target := score.Value() / score.GetMultiplier().Value()
+		if !slices.Contains(Targets(), target) {
+			continue
+		}
+
+		for range score.GetMultiplier().Value() {
+			turn.Marks++
+
+			if marks[target] < MarksToClose {
+				marks[target]++
+				continue
+			}
+
+			if b.openForOthers(player, target) {
+				turn.Points += target
+			}
+		}
+	}
+
+	b.points[player] += turn.Points
+
+	return turn, nil
+}
+
+// Marks returns the marks of a player on the given target
+func (b *Board) Marks(player string, target int) int {
+	return b.marks[player][target]
+}
+
+// Points returns the points scored by a player
+func (b *Board) Points(player string) int {
+	return b.points[player]
+}
+
+// IsClosed returns true if the given target was closed by all players
+func (b *Board) IsClosed(target int) bool {
+	for _, p := range b.players {
+		if b.marks[p][target] < MarksToClose {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Winner returns the player who closed all targets and has at least as many points as every other player
+func (b *Board) Winner() (string, bool) {
+	for _, p := range b.players {
+		if !b.closedAll(p) {
+			continue
+		}
+
+		leading := true
+		for _, other := range b.players {
+			if b.points[other] > b.points[p] {
+				leading = false
+				break
+			}
+		}
+
+		if leading {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// Ranking orders the players by their points, ties are resolved by the amount of marks
+func (b *Board) Ranking() []string {
+	winner, _ := b.Winner()
+
+	var ranking []string
+	ranking = append(ranking, b.players...)
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		switch {
+		case ranking[i] == winner:
+			return true
+		case ranking[j] == winner:
+			return false
+		case b.points[ranking[i]] != b.points[ranking[j]]:
+			return b.points[ranking[i]] > b.points[ranking[j]]
+		default:
+			return b.totalMarks(ranking[i]) > b.totalMarks(ranking[j])
+		}
+	})
+
+	return ranking
+}
+
+func (b *Board) openForOthers(player string, target int) bool {
+	for _, p := range b.players {
+		if p == player {
+			continue
+		}
+
+		if b.marks[p][target] < MarksToClose {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Board) closedAll(player string) bool {
+	for _, target := range Targets() {
+		if b.marks[player][target] < MarksToClose {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b *Board) totalMarks(player string) int {
+	total := 0
+
+	for _, marks := range b.marks[player] {
+		total += marks
+	}
+
+	return total
+}
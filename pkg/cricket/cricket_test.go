@@ -0,0 +1,80 @@
+package cricket
+
+import (
+	"testing"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, fields ...string) []*checkout.Score {
+	var scores []*checkout.Score
+
+	for _, f := range fields {
+		s, err := checkout.ParseScore(f)
+		require.NoError(t, err)
+		scores = append(scores, s)
+	}
+
+	return scores
+}
+
+func TestBoard_Throw(t *testing.T) {
+	b := NewBoard([]string{"a", "b"})
+
+	turn, err := b.Throw("a", parse(t, "T20", "20", "D20"))
+	require.NoError(t, err)
+	assert.Equal(t, Turn{Points: 60, Marks: 6}, turn)
+	assert.Equal(t, 3, b.Marks("a", 20))
+	assert.Equal(t, 60, b.Points("a"))
+
+	turn, err = b.Throw("b", parse(t, "1", "T20", "D19"))
+	require.NoError(t, err)
+	assert.Equal(t, Turn{Points: 0, Marks: 5}, turn)
+	assert.True(t, b.IsClosed(20))
+	assert.False(t, b.IsClosed(19))
+
+	// 20 is closed by everyone, no more points
+	turn, err = b.Throw("a", parse(t, "T20"))
+	require.NoError(t, err)
+	assert.Equal(t, Turn{Points: 0, Marks: 3}, turn)
+
+	turn, err = b.Throw("b", parse(t, "DB", "B", "B"))
+	require.NoError(t, err)
+	assert.Equal(t, Turn{Points: 25, Marks: 4}, turn)
+	assert.Equal(t, 25, b.Points("b"))
+
+	_, err = b.Throw("c", parse(t, "T20"))
+	require.Error(t, err)
+}
+
+func TestBoard_Winner(t *testing.T) {
+	b := NewBoard([]string{"a", "b"})
+
+	_, ok := b.Winner()
+	assert.False(t, ok)
+
+	_, err := b.Throw("b", parse(t, "T15", "T15"))
+	require.NoError(t, err)
+
+	for _, target := range []string{"T20", "T19", "T18", "T17", "T16", "T15"} {
+		_, err := b.Throw("a", parse(t, target))
+		require.NoError(t, err)
+	}
+	_, err = b.Throw("a", parse(t, "DB", "B"))
+	require.NoError(t, err)
+
+	// a closed everything, but b has more points
+	_, ok = b.Winner()
+	assert.False(t, ok)
+	assert.Equal(t, []string{"b", "a"}, b.Ranking())
+
+	_, err = b.Throw("a", parse(t, "T20", "T20"))
+	require.NoError(t, err)
+
+	winner, ok := b.Winner()
+	assert.True(t, ok)
+	assert.Equal(t, "a", winner)
+	assert.Equal(t, []string{"a", "b"}, b.Ranking())
+}
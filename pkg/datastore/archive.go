@@ -0,0 +1,213 @@
+package datastore
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArchiveVersion is the version of the archive format written by this version of darts-counter
+const ArchiveVersion = 1
+
+type (
+	// Archive contains games for the transfer between databases, the players of the games are
+	// referenced by the IDs of the contained profiles
+	Archive struct {
+		Version  int              `json:"version"`
+		Exported time.Time        `json:"exported"`
+		Players  []*PlayerProfile `json:"players"`
+		Games    []*GameStats     `json:"games"`
+		Matches  []*MatchStats    `json:"matches,omitempty"`
+	}
+
+	ImportResult struct {
+		Games   int
+		Skipped int
+		Matches int
+		Players int
+	}
+)
+
+// NewArchive creates an archive of the given games, containing the profiles of their players and the matches they belong to
+func NewArchive(games []*GameStats, matches []*MatchStats, profiles []*PlayerProfile, now time.Time) *Archive {
+	a := &Archive{
+		Version:  ArchiveVersion,
+		Exported: now,
+		Players:  []*PlayerProfile{},
+		Games:    games,
+	}
+
+	if a.Games == nil {
+		a.Games = []*GameStats{}
+	}
+
+	var (
+		players  = referencedPlayers(games)
+		matchIDs = map[string]bool{}
+	)
+
+	for _, g := range games {
+		if g.MatchID != "" {
+			matchIDs[g.MatchID] = true
+		}
+	}
+
+	for _, p := range profiles {
+		if slices.Contains(players, p.ID) {
+			a.Players = append(a.Players, p)
+		}
+	}
+
+	for _, m := range matches {
+		if matchIDs[m.ID] {
+			a.Matches = append(a.Matches, m)
+		}
+	}
+
+	return a
+}
+
+func WriteArchive(w io.Writer, a *Archive) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(a)
+}
+
+func ReadArchive(r io.Reader) (*Archive, error) {
+	var a *Archive
+	err := json.NewDecoder(r).Decode(&a)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read archive: %w", err)
+	}
+
+	switch {
+	case a == nil || a.Version <= 0:
+		return nil, fmt.Errorf("file is not a darts-counter archive")
+	case a.Version > ArchiveVersion:
+		return nil, fmt.Errorf("archive has version %d, but this version of darts-counter only supports up to version %d", a.Version, ArchiveVersion)
+	}
+
+	for _, g := range a.Games {
+		if g.ID == "" {
+			return nil, fmt.Errorf("archive contains a game without id")
+		}
+	}
+
+	return a, nil
+}
+
+// WriteMovesCSV writes the moves of the given games as csv, one row per move
+func WriteMovesCSV(w io.Writer, games []*GameStats, names PlayerNames) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{
+		"game_id", "start", "game", "checkout", "checkin", "match_id", "set", "leg",
+		"round", "player", "rank", "score", "fields", "darts", "remaining", "bust", "marks", "duration",
+	})
+	if err != nil {
+		return err
+	}
+
+	optional := func(i int) string {
+		if i == 0 {
+			return ""
+		}
+		return strconv.Itoa(i)
+	}
+
+	for _, g := range games {
+		for _, m := range g.Moves {
+			darts := ""
+			if isX01(g) {
+				darts = strconv.Itoa(m.thrownDarts())
+			} else if m.Darts > 0 {
+				darts = strconv.Itoa(m.Darts)
+			}
+
+			err := cw.Write([]string{
+				g.ID,
+				g.Start.Format(time.RFC3339),
+				g.GameName(),
+				g.Checkout,
+				g.Checkin,
+				g.MatchID,
+				optional(g.Set),
+				optional(g.Leg),
+				strconv.Itoa(m.Round),
+				names.Of(m.Player),
+				optional(g.Ranks.OfPlayer(m.Player)),
+				strconv.Itoa(m.Score.Total),
+				strings.Join(m.Score.Fields, " "),
+				darts,
+				strconv.Itoa(m.Remaining),
+				strconv.FormatBool(m.Bust),
+				optional(m.Marks),
+				m.Duration,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// importPlayerIDs maps the player IDs of an archive to the IDs of the registry. Known IDs are kept, players
+// with the name of a local player are mapped to that player and all other players are added to the registry.
+func importPlayerIDs(r *registry, players []*PlayerProfile) (map[string]string, int, error) {
+	var (
+		ids   = map[string]string{}
+		added = 0
+	)
+
+	for _, p := range players {
+		if _, ok := r.byID[p.ID]; ok {
+			ids[p.ID] = p.ID
+			continue
+		}
+
+		if local, ok := r.byName[p.Name]; ok {
+			ids[p.ID] = local.ID
+			continue
+		}
+
+		err := r.put(&PlayerProfile{
+			ID:       p.ID,
+			Name:     p.Name,
+			Nickname: p.Nickname,
+			Created:  p.Created,
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to import player %q: %w", p.Name, err)
+		}
+
+		ids[p.ID] = p.ID
+		added++
+	}
+
+	return ids, added, nil
+}
+
+// referencedPlayers returns the IDs of all players referenced by the games
+func referencedPlayers(games []*GameStats) []string {
+	var ids []string
+
+	for _, g := range games {
+		for _, id := range g.Players {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,143 @@
+package datastore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive_ImportIntoOtherDatabase(t *testing.T) {
+	var (
+		pub   = newTestDatastore(t)
+		home  = newTestDatastore(t)
+		start = time.Date(2026, 2, 1, 20, 0, 0, 0, time.UTC)
+	)
+
+	settings := func(names ...string) *GameSettings {
+		s := &GameSettings{
+			Type:     config.GameType301,
+			Checkout: checkout.CheckoutTypeDoubleOut,
+			Checkin:  checkout.CheckinTypeStraightIn,
+		}
+		for _, name := range names {
+			s.Players = append(s.Players, Player{Name: name})
+		}
+		return s
+	}
+
+	// both databases know a player called Alice, but with different IDs
+	require.NoError(t, pub.UpdateGameSettings(settings("Alice", "Bob")))
+	require.NoError(t, home.UpdateGameSettings(settings("Alice")))
+
+	pubSettings, err := pub.GetGameSettings()
+	require.NoError(t, err)
+	alice, bob := pubSettings.Players[0].ID, pubSettings.Players[1].ID
+
+	game := func(id string, hour int) *GameStats {
+		return &GameStats{
+			ID:       id,
+			GameType: config.GameType301,
+			Players:  []string{alice, bob},
+			Ranks:    Ranks{1: bob, 2: alice},
+			Start:    start.Add(time.Duration(hour) * time.Hour),
+			Moves: []Move{
+				{Round: 1, Player: alice, Score: Score{Total: 60, Fields: []string{"20", "20", "20"}}, Remaining: 241, Duration: "3s"},
+				{Round: 1, Player: bob, Score: Score{Total: 301}, Remaining: 0, Duration: "5s", Darts: 3},
+			},
+		}
+	}
+
+	require.NoError(t, pub.CreateGameStats(game("g1", 0)))
+	require.NoError(t, pub.CreateGameStats(game("g2", 1)))
+
+	result, err := home.ImportArchive(&Archive{Version: ArchiveVersion})
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{}, result)
+
+	// one game was already transferred before
+	first, err := pub.ListGameStats(IdFilter("g1"))
+	require.NoError(t, err)
+	_, err = home.ImportArchive(NewArchive(first, nil, mustProfiles(t, pub), start))
+	require.NoError(t, err)
+
+	games, err := pub.ListGameStats()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, NewArchive(games, nil, mustProfiles(t, pub), start)))
+
+	archive, err := ReadArchive(&buf)
+	require.NoError(t, err)
+
+	result, err = home.ImportArchive(archive)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{Games: 1, Skipped: 1}, result)
+
+	profiles := mustProfiles(t, home)
+	require.Len(t, profiles, 2)
+	names := ToPlayerNames(profiles)
+
+	imported, err := home.ListGameStats()
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+
+	for _, g := range imported {
+		assert.Equal(t, []string{"Alice", "Bob"}, names.All(g.Players))
+		assert.NotContains(t, g.Players, alice, "alice has to be mapped to the local profile")
+	}
+
+	stats, err := home.ListPlayerStats()
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+}
+
+func TestReadArchive(t *testing.T) {
+	_, err := ReadArchive(strings.NewReader(`{"games":[]}`))
+	require.EqualError(t, err, "file is not a darts-counter archive")
+
+	_, err = ReadArchive(strings.NewReader(`{"version":99,"games":[]}`))
+	require.ErrorContains(t, err, "only supports up to version 1")
+
+	a, err := ReadArchive(strings.NewReader(`{"version":1,"games":[{"id":"g1"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, a.Games, 1)
+}
+
+func TestWriteMovesCSV(t *testing.T) {
+	games := []*GameStats{
+		{
+			ID:       "g1",
+			GameType: config.GameType301,
+			Checkout: string(checkout.CheckoutTypeDoubleOut),
+			Checkin:  string(checkout.CheckinTypeStraightIn),
+			Players:  []string{"a", "b"},
+			Ranks:    Ranks{1: "a", 2: "b"},
+			Start:    time.Date(2026, 2, 1, 20, 0, 0, 0, time.UTC),
+			Moves: []Move{
+				{Round: 1, Player: "a", Score: Score{Total: 60, Fields: []string{"20", "20", "20"}}, Remaining: 241, Duration: "3s"},
+				{Round: 1, Player: "b", Score: Score{Total: 40, Fields: []string{"D20"}}, Remaining: 0, Duration: "1.5s"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMovesCSV(&buf, games, PlayerNames{"a": "Alice", "b": "Bob"}))
+
+	assert.Equal(t, `game_id,start,game,checkout,checkin,match_id,set,leg,round,player,rank,score,fields,darts,remaining,bust,marks,duration
+g1,2026-02-01T20:00:00Z,301,double-out,straight-in,,,,1,Alice,1,60,20 20 20,3,241,false,,3s
+g1,2026-02-01T20:00:00Z,301,double-out,straight-in,,,,1,Bob,2,40,D20,1,0,false,,1.5s
+`, buf.String())
+}
+
+func mustProfiles(t *testing.T, ds Datastore) []*PlayerProfile {
+	profiles, err := ds.ListPlayerProfiles()
+	require.NoError(t, err)
+	return profiles
+}
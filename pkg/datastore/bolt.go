@@ -5,22 +5,26 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/Gerrit91/darts-counter/pkg/config"
 
 	bolt "go.etcd.io/bbolt"
-	berrors "go.etcd.io/bbolt/errors"
 )
 
 var (
-	gamesBucket    = []byte("games")
-	settingsBucket = []byte("settings")
+	checkpointsBucket = []byte("checkpoints")
+	gamesBucket       = []byte("games")
+	matchesBucket     = []byte("matches")
+	playersBucket     = []byte("players")
+	settingsBucket    = []byte("settings")
 )
 
 const (
-	settingsGameKey string = "game"
+	checkpointGameKey string = "game"
+	settingsGameKey   string = "game"
 )
 
 type boltImpl struct {
@@ -32,132 +36,596 @@ type boltImpl struct {
 func (b *boltImpl) ListGameStats(filterOpts ...filter) ([]*GameStats, error) {
 	var gs []*GameStats
 
-	for _, opt := range filterOpts {
-		switch o := opt.(type) {
-		case *idFilter:
-			err := b.db.View(func(tx *bolt.Tx) error {
-				b := tx.Bucket(gamesBucket)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		gs, _, err = listGames(tx, "", 0, filterOpts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-				v := b.Get([]byte(o.id))
-				if v == nil {
-					return fmt.Errorf("%w: game with id %q not found", ErrNotFound, o.id)
-				}
+	return gs, nil
+}
 
-				var s *GameStats
-				err := json.Unmarshal(v, &s)
-				if err != nil {
-					return err
-				}
+func (b *boltImpl) ListGameStatsPage(after string, limit int, filterOpts ...filter) (*GameStatsPage, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("page limit must be positive")
+	}
 
-				gs = append(gs, s)
+	page := &GameStatsPage{}
 
-				return nil
-			})
-			if err != nil {
-				return nil, err
-			}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		page.Games, page.Next, err = listGames(tx, after, limit, filterOpts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (b *boltImpl) CreateGameStats(gameStats *GameStats) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(gamesBucket)
+
+		// the player statistics can only be extended with the latest game because of the ratings
+		incremental := b.Get([]byte(gameStats.ID)) == nil && isLatestGame(tx, gameStats)
+
+		err := unindexStoredGame(tx, gameStats.ID)
+		if err != nil {
+			return err
+		}
+
+		buf, err := json.Marshal(gameStats)
+		if err != nil {
+			return err
+		}
+
+		err = b.Put([]byte(gameStats.ID), buf)
+		if err != nil {
+			return err
+		}
+
+		err = indexGame(tx, gameStats)
+		if err != nil {
+			return err
+		}
+
+		if !incremental {
+			return rebuildPlayerStats(tx)
+		}
+
+		return addGameToPlayerStats(tx, gameStats)
+	})
+}
+
+func (b *boltImpl) DeleteGameStats(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(gamesBucket)
 
-			return gs, nil
+		if b.Get([]byte(id)) == nil {
+			return nil
+		}
 
-		default:
-			return nil, fmt.Errorf("internal error: unsupported option %T", o)
+		err := unindexStoredGame(tx, id)
+		if err != nil {
+			return err
 		}
+
+		err = b.Delete([]byte(id))
+		if err != nil {
+			return err
+		}
+
+		// the statistics cannot be reverted because of maximum values and ratings
+		return rebuildPlayerStats(tx)
+	})
+}
+
+// unindexStoredGame removes the stored version of a game from the indexes, if there is any
+func unindexStoredGame(tx *bolt.Tx, id string) error {
+	v := tx.Bucket(gamesBucket).Get([]byte(id))
+	if v == nil {
+		return nil
+	}
+
+	var gs *GameStats
+	err := json.Unmarshal(v, &gs)
+	if err != nil {
+		return err
 	}
 
+	return unindexGame(tx, gs)
+}
+
+func (b *boltImpl) CreateMatchStats(matchStats *MatchStats) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(matchesBucket)
+
+		buf, err := json.Marshal(matchStats)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(matchStats.ID), buf)
+	})
+}
+
+func (b *boltImpl) ListMatchStats() ([]*MatchStats, error) {
+	var ms []*MatchStats
+
 	err := b.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(gamesBucket)
+		b := tx.Bucket(matchesBucket)
 
-		err := b.ForEach(func(k, v []byte) error {
-			var s *GameStats
-			err := json.Unmarshal(v, &s)
+		return b.ForEach(func(k, v []byte) error {
+			var m *MatchStats
+			err := json.Unmarshal(v, &m)
 			if err != nil {
 				return err
 			}
 
-			gs = append(gs, s)
+			ms = append(ms, m)
 
 			return nil
 		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].Start.Before(ms[j].Start)
+	})
+
+	return ms, nil
+}
+
+func (b *boltImpl) GetGameSettings() (*GameSettings, error) {
+	var s *GameSettings
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		s, err = getSettings(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func getSettings(tx *bolt.Tx) (*GameSettings, error) {
+	v := tx.Bucket(settingsBucket).Get([]byte(settingsGameKey))
+	if v == nil {
+		return nil, fmt.Errorf("%w: settings with id %q not found", ErrNotFound, settingsGameKey)
+	}
+
+	var s *GameSettings
+	err := json.Unmarshal(v, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func putSettings(tx *bolt.Tx, s *GameSettings) error {
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(settingsBucket).Put([]byte(settingsGameKey), buf)
+}
+
+// rewriteGames stores every game that was changed by the given function
+func rewriteGames(tx *bolt.Tx, rewrite func(gs *GameStats) (bool, error)) error {
+	b := tx.Bucket(gamesBucket)
+
+	var changed []*GameStats
+
+	err := b.ForEach(func(k, v []byte) error {
+		var gs *GameStats
+		err := json.Unmarshal(v, &gs)
 		if err != nil {
 			return err
 		}
 
+		ok, err := rewrite(gs)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			changed = append(changed, gs)
+		}
+
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	sort.Slice(gs, func(i, j int) bool {
-		return gs[i].Start.Before(gs[j].Start)
-	})
+	// modifying a bucket while iterating over it is not allowed
+	for _, gs := range changed {
+		err := unindexStoredGame(tx, gs.ID)
+		if err != nil {
+			return err
+		}
 
-	return gs, nil
+		buf, err := json.Marshal(gs)
+		if err != nil {
+			return err
+		}
+
+		err = b.Put([]byte(gs.ID), buf)
+		if err != nil {
+			return err
+		}
+
+		err = indexGame(tx, gs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(changed) > 0 && tx.Bucket(playerStatsBucket) != nil {
+		return rebuildPlayerStats(tx)
+	}
+
+	return nil
 }
 
-func (b *boltImpl) CreateGameStats(gameStats *GameStats) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(gamesBucket)
+// rewriteMatches stores every match that was changed by the given function
+func rewriteMatches(tx *bolt.Tx, rewrite func(ms *MatchStats) bool) error {
+	b := tx.Bucket(matchesBucket)
 
-		buf, err := json.Marshal(gameStats)
+	var changed []*MatchStats
+
+	err := b.ForEach(func(k, v []byte) error {
+		var ms *MatchStats
+		err := json.Unmarshal(v, &ms)
 		if err != nil {
 			return err
 		}
 
-		return b.Put([]byte(gameStats.ID), buf)
+		if rewrite(ms) {
+			changed = append(changed, ms)
+		}
+
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	for _, ms := range changed {
+		buf, err := json.Marshal(ms)
+		if err != nil {
+			return err
+		}
+
+		err = b.Put([]byte(ms.ID), buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (b *boltImpl) DeleteGameStats(id string) error {
+func (b *boltImpl) UpdateGameSettings(s *GameSettings) error {
+	if err := validateGameSettings(s); err != nil {
+		return err
+	}
+
 	return b.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(gamesBucket)
+		r, err := loadRegistry(tx)
+		if err != nil {
+			return err
+		}
+
+		for i, p := range s.Players {
+			profile, ok := r.byID[p.ID]
+			if !ok {
+				id, err := r.resolve(p.Name, time.Now())
+				if err != nil {
+					return err
+				}
+
+				s.Players[i].ID = id
+				continue
+			}
+
+			if profile.Name != p.Name {
+				if other, ok := r.byName[p.Name]; ok {
+					// the name belongs to another player, so the player was replaced
+					s.Players[i].ID = other.ID
+					continue
+				}
+
+				// renaming a player in the settings renames the profile
+				renamed := *profile
+				renamed.Name = p.Name
+
+				err = r.put(&renamed)
+				if err != nil {
+					return err
+				}
+			}
+		}
 
-		return b.Delete([]byte(id))
+		return putSettings(tx, s)
 	})
 }
 
-func (b *boltImpl) GetGameSettings() (*GameSettings, error) {
-	var s *GameSettings
+func (b *boltImpl) GetCheckpoint() (*Checkpoint, error) {
+	var c *Checkpoint
 
 	err := b.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(settingsBucket)
+		b := tx.Bucket(checkpointsBucket)
 
-		v := b.Get([]byte(settingsGameKey))
+		v := b.Get([]byte(checkpointGameKey))
 		if v == nil {
-			return fmt.Errorf("%w: settings with id %q not found", ErrNotFound, settingsGameKey)
+			return fmt.Errorf("%w: checkpoint with id %q not found", ErrNotFound, checkpointGameKey)
+		}
+
+		return json.Unmarshal(v, &c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (b *boltImpl) UpdateCheckpoint(c *Checkpoint) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(checkpointsBucket)
+
+		buf, err := json.Marshal(c)
+		if err != nil {
+			return err
 		}
 
-		err := json.Unmarshal(v, &s)
+		return b.Put([]byte(checkpointGameKey), buf)
+	})
+}
+
+func (b *boltImpl) DeleteCheckpoint() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(checkpointsBucket)
+
+		return b.Delete([]byte(checkpointGameKey))
+	})
+}
+
+func (b *boltImpl) ListPlayerProfiles() ([]*PlayerProfile, error) {
+	var ps []*PlayerProfile
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		r, err := loadRegistry(tx)
 		if err != nil {
 			return err
 		}
 
+		ps = r.list()
+
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return s, nil
+	return ps, nil
 }
 
-func (b *boltImpl) UpdateGameSettings(s *GameSettings) error {
-	if err := validateGameSettings(s); err != nil {
-		return err
-	}
+func (b *boltImpl) UpdatePlayerProfile(p *PlayerProfile) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		r, err := loadRegistry(tx)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := r.byID[p.ID]; !ok {
+			return fmt.Errorf("%w: player with id %q not found", ErrNotFound, p.ID)
+		}
+
+		err = r.put(p)
+		if err != nil {
+			return err
+		}
 
+		s, err := getSettings(tx)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		for i := range s.Players {
+			if s.Players[i].ID == p.ID {
+				s.Players[i].Name = p.Name
+			}
+		}
+
+		return putSettings(tx, s)
+	})
+}
+
+func (b *boltImpl) MergePlayerProfiles(fromID, intoID string) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(settingsBucket)
+		r, err := loadRegistry(tx)
+		if err != nil {
+			return err
+		}
+
+		if fromID == intoID {
+			return fmt.Errorf("cannot merge a player into itself")
+		}
 
-		buf, err := json.Marshal(s)
+		for _, id := range []string{fromID, intoID} {
+			if _, ok := r.byID[id]; !ok {
+				return fmt.Errorf("%w: player with id %q not found", ErrNotFound, id)
+			}
+		}
+
+		rename := func(id string) string {
+			if id == fromID {
+				return intoID
+			}
+			return id
+		}
+
+		err = rewriteGames(tx, func(gs *GameStats) (bool, error) {
+			if slices.Contains(gs.Players, fromID) && slices.Contains(gs.Players, intoID) {
+				return false, fmt.Errorf("cannot merge players who played in the same game (%s)", gs.ID)
+			}
+
+			return gs.renamePlayers(rename), nil
+		})
 		if err != nil {
 			return err
 		}
 
-		return b.Put([]byte(settingsGameKey), buf)
+		err = rewriteMatches(tx, func(ms *MatchStats) bool {
+			return ms.renamePlayers(rename)
+		})
+		if err != nil {
+			return err
+		}
+
+		s, err := getSettings(tx)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if s != nil {
+			into := r.byID[intoID]
+
+			var players []Player
+			for _, p := range s.Players {
+				if p.ID == fromID {
+					if slices.ContainsFunc(s.Players, func(other Player) bool { return other.ID == intoID }) {
+						continue
+					}
+
+					p.ID = into.ID
+					p.Name = into.Name
+				}
+
+				players = append(players, p)
+			}
+
+			s.Players = players
+
+			err = putSettings(tx, s)
+			if err != nil {
+				return err
+			}
+		}
+
+		return r.delete(fromID)
 	})
 }
 
+func (b *boltImpl) ImportArchive(a *Archive) (*ImportResult, error) {
+	result := &ImportResult{}
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		r, err := loadRegistry(tx)
+		if err != nil {
+			return err
+		}
+
+		ids, added, err := importPlayerIDs(r, a.Players)
+		if err != nil {
+			return err
+		}
+
+		result.Players = added
+
+		for _, id := range referencedPlayers(a.Games) {
+			if _, ok := ids[id]; !ok {
+				return fmt.Errorf("%w: archive references player %q without profile", ErrNotFound, id)
+			}
+		}
+
+		rename := func(id string) string {
+			if local, ok := ids[id]; ok {
+				return local
+			}
+			return id
+		}
+
+		var (
+			games   = tx.Bucket(gamesBucket)
+			matches = tx.Bucket(matchesBucket)
+		)
+
+		for _, gs := range a.Games {
+			if games.Get([]byte(gs.ID)) != nil {
+				result.Skipped++
+				continue
+			}
+
+			gs.renamePlayers(rename)
+
+			buf, err := json.Marshal(gs)
+			if err != nil {
+				return err
+			}
+
+			err = games.Put([]byte(gs.ID), buf)
+			if err != nil {
+				return err
+			}
+
+			err = indexGame(tx, gs)
+			if err != nil {
+				return err
+			}
+
+			result.Games++
+		}
+
+		for _, ms := range a.Matches {
+			if matches.Get([]byte(ms.ID)) != nil {
+				continue
+			}
+
+			ms.renamePlayers(rename)
+
+			buf, err := json.Marshal(ms)
+			if err != nil {
+				return err
+			}
+
+			err = matches.Put([]byte(ms.ID), buf)
+			if err != nil {
+				return err
+			}
+
+			result.Matches++
+		}
+
+		if result.Games == 0 {
+			return nil
+		}
+
+		return rebuildPlayerStats(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (b *boltImpl) Close() {
 	if b.db != nil {
 		if err := b.db.Close(); err != nil {
@@ -176,18 +644,10 @@ func (b *boltImpl) initializeDatastore() error {
 
 	b.db = db
 
-	for _, bucket := range [][]byte{gamesBucket, settingsBucket} {
-		err = db.Update(func(tx *bolt.Tx) error {
-			_, err = tx.CreateBucket(bucket)
-			if err != nil {
-				return fmt.Errorf("error creating bucket %s: %w", string(bucket), err)
-			}
-
-			return nil
-		})
-		if err != nil && !errors.Is(err, berrors.ErrBucketExists) {
-			return err
-		}
+	err = b.migrate()
+	if err != nil {
+		b.Close()
+		return err
 	}
 
 	return nil
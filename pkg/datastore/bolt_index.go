@@ -0,0 +1,268 @@
+package datastore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// the index buckets map a start key of a game to its id, a start key consists of the start time
+// as big endian unix nanoseconds followed by the game id, such that keys are ordered by start time
+var (
+	gamesByStartBucket  = []byte("games-by-start")
+	gamesByPlayerBucket = []byte("games-by-player")
+)
+
+func startKey(g *GameStats) []byte {
+	return append(timeKey(g.Start), []byte(g.ID)...)
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(max(t.UnixNano(), 0)))
+	return key
+}
+
+// indexGame adds a game to the index buckets, it does nothing when the indexes were not yet built
+func indexGame(tx *bolt.Tx, g *GameStats) error {
+	byStart := tx.Bucket(gamesByStartBucket)
+	byPlayer := tx.Bucket(gamesByPlayerBucket)
+	if byStart == nil || byPlayer == nil {
+		return nil
+	}
+
+	key := startKey(g)
+
+	err := byStart.Put(key, []byte(g.ID))
+	if err != nil {
+		return err
+	}
+
+	for _, id := range g.Players {
+		pb, err := byPlayer.CreateBucketIfNotExists([]byte(id))
+		if err != nil {
+			return err
+		}
+
+		err = pb.Put(key, []byte(g.ID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unindexGame removes a game from the index buckets
+func unindexGame(tx *bolt.Tx, g *GameStats) error {
+	byStart := tx.Bucket(gamesByStartBucket)
+	byPlayer := tx.Bucket(gamesByPlayerBucket)
+	if byStart == nil || byPlayer == nil {
+		return nil
+	}
+
+	key := startKey(g)
+
+	err := byStart.Delete(key)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range g.Players {
+		pb := byPlayer.Bucket([]byte(id))
+		if pb == nil {
+			continue
+		}
+
+		err = pb.Delete(key)
+		if err != nil {
+			return err
+		}
+
+		if k, _ := pb.Cursor().First(); k == nil {
+			err = byPlayer.DeleteBucket([]byte(id))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateGameIndexes builds the index buckets from the stored games, it is a no-op when the indexes exist already
+func migrateGameIndexes(tx *bolt.Tx) error {
+	if tx.Bucket(gamesByStartBucket) != nil && tx.Bucket(gamesByPlayerBucket) != nil {
+		return nil
+	}
+
+	for _, bucket := range [][]byte{gamesByStartBucket, gamesByPlayerBucket} {
+		if tx.Bucket(bucket) != nil {
+			err := tx.DeleteBucket(bucket)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.CreateBucket(bucket)
+		if err != nil {
+			return fmt.Errorf("error creating bucket %s: %w", string(bucket), err)
+		}
+	}
+
+	return tx.Bucket(gamesBucket).ForEach(func(k, v []byte) error {
+		var gs *GameStats
+		err := json.Unmarshal(v, &gs)
+		if err != nil {
+			return err
+		}
+
+		return indexGame(tx, gs)
+	})
+}
+
+func (b *boltImpl) CountGamesByPlayer() (map[string]int, error) {
+	counts := map[string]int{}
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		byPlayer := tx.Bucket(gamesByPlayerBucket)
+
+		return byPlayer.ForEachBucket(func(k []byte) error {
+			counts[string(k)] = byPlayer.Bucket(k).Stats().KeyN
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// listGames walks through the games in the order of their start time and returns the games matching the filters.
+// The player index is used when filtering by player, a date range narrows down the range of the walk.
+// At most limit games are returned (zero means no limit), the returned cursor points to the last returned game
+// and is empty if the walk reached the end.
+func listGames(tx *bolt.Tx, after string, limit int, filterOpts ...filter) ([]*GameStats, string, error) {
+	var (
+		games    = tx.Bucket(gamesBucket)
+		index    *bolt.Bucket
+		id       *idFilter
+		matchers []gameMatcher
+		from, to []byte
+	)
+
+	for _, opt := range filterOpts {
+		switch o := opt.(type) {
+		case *idFilter:
+			id = o
+			continue
+		case *playerFilter:
+			if index == nil {
+				index = tx.Bucket(gamesByPlayerBucket).Bucket([]byte(o.id))
+				if index == nil {
+					// the player has not played any game yet
+					return nil, "", nil
+				}
+			}
+		case *dateRangeFilter:
+			if !o.from.IsZero() && (from == nil || bytes.Compare(timeKey(o.from), from) > 0) {
+				from = timeKey(o.from)
+			}
+			if !o.to.IsZero() && (to == nil || bytes.Compare(timeKey(o.to), to) < 0) {
+				to = timeKey(o.to)
+			}
+		}
+
+		m, ok := opt.(gameMatcher)
+		if !ok {
+			return nil, "", fmt.Errorf("internal error: unsupported option %T", opt)
+		}
+
+		matchers = append(matchers, m)
+	}
+
+	if id != nil {
+		v := games.Get([]byte(id.id))
+		if v == nil {
+			return nil, "", fmt.Errorf("%w: game with id %q not found", ErrNotFound, id.id)
+		}
+
+		var gs *GameStats
+		err := json.Unmarshal(v, &gs)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !matchesAll(gs, matchers) {
+			return nil, "", nil
+		}
+
+		return []*GameStats{gs}, "", nil
+	}
+
+	if index == nil {
+		index = tx.Bucket(gamesByStartBucket)
+	}
+
+	var (
+		result []*GameStats
+		c      = index.Cursor()
+		k, v   []byte
+	)
+
+	switch {
+	case after != "":
+		key, err := hex.DecodeString(after)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid page cursor %q", after)
+		}
+
+		k, v = c.Seek(key)
+		if bytes.Equal(k, key) {
+			k, v = c.Next()
+		}
+	case from != nil:
+		k, v = c.Seek(from)
+	default:
+		k, v = c.First()
+	}
+
+	for ; k != nil; k, v = c.Next() {
+		if to != nil && bytes.Compare(k, to) >= 0 {
+			break
+		}
+
+		raw := games.Get(v)
+		if raw == nil {
+			return nil, "", fmt.Errorf("index references missing game %q", string(v))
+		}
+
+		var gs *GameStats
+		err := json.Unmarshal(raw, &gs)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !matchesAll(gs, matchers) {
+			continue
+		}
+
+		result = append(result, gs)
+
+		if limit > 0 && len(result) == limit {
+			if next, _ := c.Next(); next == nil {
+				return result, "", nil
+			}
+
+			return result, hex.EncodeToString(k), nil
+		}
+	}
+
+	return result, "", nil
+}
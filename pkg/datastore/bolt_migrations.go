@@ -0,0 +1,147 @@
+package datastore
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var metaBucket = []byte("meta")
+
+const schemaVersionKey string = "schema_version"
+
+type (
+	migration struct {
+		description string
+		migrate     func(tx *bolt.Tx) error
+	}
+)
+
+// migrations are run in order when the database is opened, the schema version of a database is the number of
+// migrations that were applied to it. New migrations must only be appended to this list.
+//
+// Databases from before the schema version was introduced have version 0 regardless of their actual state,
+// which is why the migrations up to the schema version 4 are no-ops when they are not needed.
+var migrations = []migration{
+	{description: "create buckets", migrate: createBuckets},
+	{description: "reference players by profile IDs", migrate: migratePlayerProfiles},
+	{description: "index games by start time and player", migrate: migrateGameIndexes},
+	{description: "precompute player statistics", migrate: migratePlayerStats},
+	{description: "store the form of players separately", migrate: rebuildPlayerStats},
+}
+
+func createBuckets(tx *bolt.Tx) error {
+	for _, bucket := range [][]byte{checkpointsBucket, gamesBucket, matchesBucket, playersBucket, settingsBucket} {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		if err != nil {
+			return fmt.Errorf("error creating bucket %s: %w", string(bucket), err)
+		}
+	}
+
+	return nil
+}
+
+func schemaVersion(tx *bolt.Tx) (int, error) {
+	b := tx.Bucket(metaBucket)
+	if b == nil {
+		return 0, nil
+	}
+
+	v := b.Get([]byte(schemaVersionKey))
+	if v == nil {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", string(v), err)
+	}
+
+	return version, nil
+}
+
+func putSchemaVersion(tx *bolt.Tx, version int) error {
+	b, err := tx.CreateBucketIfNotExists(metaBucket)
+	if err != nil {
+		return fmt.Errorf("error creating bucket %s: %w", string(metaBucket), err)
+	}
+
+	return b.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(version)))
+}
+
+// migrate brings the database to the latest schema version, every migration runs in its own transaction
+// together with the update of the schema version. Existing databases are backed up before.
+func (b *boltImpl) migrate() error {
+	var (
+		version int
+		empty   = true
+	)
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		version, err = schemaVersion(tx)
+		if err != nil {
+			return err
+		}
+
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			empty = false
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version > len(migrations):
+		return fmt.Errorf("database has schema version %d, but this version of darts-counter only supports up to version %d", version, len(migrations))
+	case version == len(migrations):
+		return nil
+	}
+
+	if !empty {
+		path, err := b.backup(version)
+		if err != nil {
+			return fmt.Errorf("unable to back up database before migration: %w", err)
+		}
+
+		b.log.Info("backed up database before migration", "path", path, "schema-version", version)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		m := migrations[i]
+
+		err := b.db.Update(func(tx *bolt.Tx) error {
+			err := m.migrate(tx)
+			if err != nil {
+				return err
+			}
+
+			return putSchemaVersion(tx, i+1)
+		})
+		if err != nil {
+			return fmt.Errorf("migration to schema version %d (%s) failed: %w", i+1, m.description, err)
+		}
+
+		b.log.Info("migrated database", "schema-version", i+1, "migration", m.description)
+	}
+
+	return nil
+}
+
+// backup writes a consistent copy of the database next to the database file
+func (b *boltImpl) backup(version int) (string, error) {
+	path := fmt.Sprintf("%s.v%d-%s.bak", b.c.Path, version, time.Now().Format("20060102150405"))
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(path, 0600)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,146 @@
+package datastore
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gerrit91/darts-counter/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// openFixture opens a copy of a database from the testdata directory
+func openFixture(t *testing.T, name string) (Datastore, string) {
+	src, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, src, 0600))
+
+	ds, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), &config.DatabaseConfig{Path: path})
+	require.NoError(t, err)
+
+	t.Cleanup(ds.Close)
+
+	return ds, path
+}
+
+func currentSchemaVersion(t *testing.T, ds Datastore) int {
+	var version int
+
+	err := ds.(*boltImpl).db.View(func(tx *bolt.Tx) error {
+		var err error
+		version, err = schemaVersion(tx)
+		return err
+	})
+	require.NoError(t, err)
+
+	return version
+}
+
+func TestBolt_Migrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+	}{
+		{
+			// the format of the first release, players are referenced by names
+			name:    "unversioned database",
+			fixture: "v0.db",
+		},
+		{
+			name:    "database with player profiles",
+			fixture: "v2.db",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, path := openFixture(t, tt.fixture)
+
+			assert.Equal(t, len(migrations), currentSchemaVersion(t, ds))
+
+			backups, err := filepath.Glob(path + ".v*.bak")
+			require.NoError(t, err)
+			assert.Len(t, backups, 1)
+
+			profiles, err := ds.ListPlayerProfiles()
+			require.NoError(t, err)
+			require.Len(t, profiles, 3)
+
+			names := ToPlayerNames(profiles)
+			ids := map[string]string{}
+			for id, name := range names {
+				ids[name] = id
+			}
+
+			games, err := ds.ListGameStats(PlayerFilter(ids["Bob"]))
+			require.NoError(t, err)
+			require.Len(t, games, 2)
+			assert.Equal(t, []string{"Alice", "Bob"}, names.All(games[0].Players))
+			assert.Equal(t, "Claire", names.Of(games[1].Ranks[1]))
+
+			settings, err := ds.GetGameSettings()
+			require.NoError(t, err)
+			require.Len(t, settings.Players, 2)
+			assert.Equal(t, ids["Alice"], settings.Players[0].ID)
+
+			stats, err := ds.ListPlayerStats()
+			require.NoError(t, err)
+			require.Len(t, stats, 3)
+			for _, ps := range stats {
+				if ps.Name == "Claire" {
+					assert.Equal(t, 1, ps.Scores180)
+					assert.Equal(t, 121, ps.HighestCheckout)
+				}
+			}
+		})
+	}
+}
+
+func TestBolt_MigrationsNotRepeated(t *testing.T) {
+	ds, path := openFixture(t, "v0.db")
+	ds.Close()
+
+	ds, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), &config.DatabaseConfig{Path: path})
+	require.NoError(t, err)
+	defer ds.Close()
+
+	backups, err := filepath.Glob(path + ".v*.bak")
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestBolt_NewDatabaseIsNotBackedUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.db")
+
+	ds, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), &config.DatabaseConfig{Path: path})
+	require.NoError(t, err)
+	defer ds.Close()
+
+	assert.Equal(t, len(migrations), currentSchemaVersion(t, ds))
+
+	backups, err := filepath.Glob(path + ".v*.bak")
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+}
+
+func TestBolt_NewerSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newer.db")
+
+	db, err := bolt.Open(path, 0600, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		return putSchemaVersion(tx, len(migrations)+1)
+	}))
+	require.NoError(t, db.Close())
+
+	_, err = New(slog.New(slog.NewTextHandler(io.Discard, nil)), &config.DatabaseConfig{Path: path})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only supports up to version")
+}
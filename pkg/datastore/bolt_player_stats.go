@@ -0,0 +1,246 @@
+package datastore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// the player stats bucket contains the precomputed statistics of every player, so that they do not
+// need to be computed from the whole game history when the player list is shown
+var playerStatsBucket = []byte("player-stats")
+
+// the player form bucket contains the form of every player, which grows with every game and is therefore
+// stored apart from the statistics
+var playerFormBucket = []byte("player-form")
+
+type (
+	// cachedPlayerStats is the stored form of the player statistics including the running totals,
+	// which are required to add further games
+	cachedPlayerStats struct {
+		Stats  *PlayerStats `json:"stats"`
+		Totals playerTotals `json:"totals"`
+	}
+)
+
+func (b *boltImpl) ListPlayerStats() ([]*PlayerStats, error) {
+	var ps []*PlayerStats
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		r, err := loadRegistry(tx)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(playerStatsBucket).ForEach(func(k, v []byte) error {
+			p, err := unmarshalPlayerStats(v)
+			if err != nil {
+				return err
+			}
+
+			if profile, ok := r.byID[p.ID]; ok {
+				p.Name = profile.Name
+			} else {
+				p.Name = p.ID
+			}
+
+			ps = append(ps, p)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+func (b *boltImpl) GetPlayerForm(id string) ([]FormPoint, error) {
+	var form []FormPoint
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(playerFormBucket).Bucket([]byte(id))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var point FormPoint
+			err := json.Unmarshal(v, &point)
+			if err != nil {
+				return err
+			}
+
+			form = append(form, point)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return form, nil
+}
+
+func (b *boltImpl) RebuildPlayerStats() error {
+	return b.db.Update(rebuildPlayerStats)
+}
+
+// isLatestGame returns true if the game started after all indexed games
+func isLatestGame(tx *bolt.Tx, g *GameStats) bool {
+	k, _ := tx.Bucket(gamesByStartBucket).Cursor().Last()
+	return k == nil || bytes.Compare(k, startKey(g)) < 0
+}
+
+// addGameToPlayerStats updates the stored statistics of the players of a game, the game has to be
+// the latest game because the ratings depend on the order of the games
+func addGameToPlayerStats(tx *bolt.Tx, g *GameStats) error {
+	var (
+		bucket  = tx.Bucket(playerStatsBucket)
+		players = map[string]*PlayerStats{}
+	)
+
+	for _, id := range g.Players {
+		v := bucket.Get([]byte(id))
+		if v == nil {
+			continue
+		}
+
+		p, err := unmarshalPlayerStats(v)
+		if err != nil {
+			return err
+		}
+
+		players[id] = p
+	}
+
+	err := addGame(players, g)
+	if err != nil {
+		return err
+	}
+
+	return putPlayerStats(tx, players)
+}
+
+// rebuildPlayerStats computes the statistics of all players from the stored games
+func rebuildPlayerStats(tx *bolt.Tx) error {
+	for _, bucket := range [][]byte{playerStatsBucket, playerFormBucket} {
+		if tx.Bucket(bucket) != nil {
+			err := tx.DeleteBucket(bucket)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.CreateBucket(bucket)
+		if err != nil {
+			return fmt.Errorf("error creating bucket %s: %w", string(bucket), err)
+		}
+	}
+
+	games, _, err := listGames(tx, "", 0)
+	if err != nil {
+		return err
+	}
+
+	players := map[string]*PlayerStats{}
+
+	for _, g := range games {
+		err := addGame(players, g)
+		if err != nil {
+			return err
+		}
+	}
+
+	return putPlayerStats(tx, players)
+}
+
+// migratePlayerStats builds the player statistics, it is a no-op when they exist already
+func migratePlayerStats(tx *bolt.Tx) error {
+	if tx.Bucket(playerStatsBucket) != nil {
+		return nil
+	}
+
+	return rebuildPlayerStats(tx)
+}
+
+// putPlayerStats stores the statistics of the given players, their form is appended to the stored form
+func putPlayerStats(tx *bolt.Tx, players map[string]*PlayerStats) error {
+	bucket := tx.Bucket(playerStatsBucket)
+
+	for id, p := range players {
+		p.finalize()
+
+		err := appendPlayerForm(tx, id, p.Form)
+		if err != nil {
+			return err
+		}
+
+		stats := *p
+		stats.Form = nil
+
+		buf, err := json.Marshal(cachedPlayerStats{Stats: &stats, Totals: p.totals})
+		if err != nil {
+			return err
+		}
+
+		err = bucket.Put([]byte(id), buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendPlayerForm adds form points to the bucket of a player, every point has its own key in the order
+// of the games, so that a game does not rewrite the whole form
+func appendPlayerForm(tx *bolt.Tx, id string, points []FormPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	bucket, err := tx.Bucket(playerFormBucket).CreateBucketIfNotExists([]byte(id))
+	if err != nil {
+		return err
+	}
+
+	for _, point := range points {
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		buf, err := json.Marshal(point)
+		if err != nil {
+			return err
+		}
+
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+
+		err = bucket.Put(key, buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unmarshalPlayerStats(v []byte) (*PlayerStats, error) {
+	var c cachedPlayerStats
+	err := json.Unmarshal(v, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Stats.totals = c.Totals
+
+	return c.Stats, nil
+}
@@ -0,0 +1,240 @@
+package datastore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type registry struct {
+	bucket *bolt.Bucket
+	byID   map[string]*PlayerProfile
+	byName map[string]*PlayerProfile
+}
+
+func loadRegistry(tx *bolt.Tx) (*registry, error) {
+	r := &registry{
+		bucket: tx.Bucket(playersBucket),
+		byID:   map[string]*PlayerProfile{},
+		byName: map[string]*PlayerProfile{},
+	}
+
+	err := r.bucket.ForEach(func(k, v []byte) error {
+		var p *PlayerProfile
+		err := json.Unmarshal(v, &p)
+		if err != nil {
+			return err
+		}
+
+		r.byID[p.ID] = p
+		r.byName[p.Name] = p
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *registry) list() []*PlayerProfile {
+	var ps []*PlayerProfile
+	for _, p := range r.byID {
+		ps = append(ps, p)
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].Name < ps[j].Name
+	})
+
+	return ps
+}
+
+// resolve returns the ID of the player with the given name, a new profile is created if there is none
+func (r *registry) resolve(name string, created time.Time) (string, error) {
+	if p, ok := r.byName[name]; ok {
+		return p.ID, nil
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", fmt.Errorf("unable to generate uuid: %w", err)
+	}
+
+	p := &PlayerProfile{
+		ID:      id.String(),
+		Name:    name,
+		Created: created,
+	}
+
+	err = r.put(p)
+	if err != nil {
+		return "", err
+	}
+
+	return p.ID, nil
+}
+
+func (r *registry) put(p *PlayerProfile) error {
+	err := validatePlayerProfile(p, r.list())
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	if old, ok := r.byID[p.ID]; ok {
+		delete(r.byName, old.Name)
+	}
+
+	r.byID[p.ID] = p
+	r.byName[p.Name] = p
+
+	return r.bucket.Put([]byte(p.ID), buf)
+}
+
+func (r *registry) delete(id string) error {
+	if old, ok := r.byID[id]; ok {
+		delete(r.byName, old.Name)
+	}
+
+	delete(r.byID, id)
+
+	return r.bucket.Delete([]byte(id))
+}
+
+// migratePlayerProfiles creates player profiles for games that still reference players by their names
+// and rewrites these references to the profile IDs, it is a no-op when all references are IDs already
+func migratePlayerProfiles(tx *bolt.Tx) error {
+	r, err := loadRegistry(tx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		ids     = map[string]string{}
+		resolve = func(name string, seen time.Time) error {
+			if _, ok := r.byID[name]; ok || name == "" {
+				return nil
+			}
+
+			id, err := r.resolve(name, seen)
+			if err != nil {
+				return err
+			}
+
+			ids[name] = id
+
+			return nil
+		}
+		rename = func(name string) string {
+			if id, ok := ids[name]; ok {
+				return id
+			}
+			return name
+		}
+	)
+
+	// games are resolved first, so profiles get the date of the first game as creation date
+	var games []*GameStats
+	err = tx.Bucket(gamesBucket).ForEach(func(k, v []byte) error {
+		var gs *GameStats
+		err := json.Unmarshal(v, &gs)
+		if err != nil {
+			return err
+		}
+
+		games = append(games, gs)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].Start.Before(games[j].Start)
+	})
+
+	for _, gs := range games {
+		for _, p := range gs.Players {
+			if err := resolve(p, gs.Start); err != nil {
+				return err
+			}
+		}
+	}
+
+	err = tx.Bucket(matchesBucket).ForEach(func(k, v []byte) error {
+		var ms *MatchStats
+		err := json.Unmarshal(v, &ms)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range ms.Players {
+			if err := resolve(p, ms.Start); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(ids) > 0 {
+		err = rewriteGames(tx, func(gs *GameStats) (bool, error) {
+			return gs.renamePlayers(rename), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = rewriteMatches(tx, func(ms *MatchStats) bool {
+			return ms.renamePlayers(rename)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	s, err := getSettings(tx)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	changed := false
+	for i, p := range s.Players {
+		if _, ok := r.byID[p.ID]; ok {
+			continue
+		}
+
+		id, err := r.resolve(p.Name, time.Now())
+		if err != nil {
+			return err
+		}
+
+		s.Players[i].ID = id
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return putSettings(tx, s)
+}
@@ -0,0 +1,220 @@
+package datastore
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDatastore(t *testing.T) Datastore {
+	ds, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), &config.DatabaseConfig{
+		Path: filepath.Join(t.TempDir(), "test.db"),
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(ds.Close)
+
+	return ds
+}
+
+func TestBolt_ListGameStatsPage(t *testing.T) {
+	var (
+		ds    = newTestDatastore(t)
+		start = time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	)
+
+	// profiles are created when players are stored in the settings
+	require.NoError(t, ds.UpdateGameSettings(&GameSettings{
+		Type:     config.GameType501,
+		Checkout: checkout.CheckoutTypeDoubleOut,
+		Checkin:  checkout.CheckinTypeStraightIn,
+		Players:  []Player{{Name: "Alice"}, {Name: "Bob"}, {Name: "Claire"}},
+	}))
+
+	profiles, err := ds.ListPlayerProfiles()
+	require.NoError(t, err)
+
+	ids := map[string]string{}
+	for _, p := range profiles {
+		ids[p.Name] = p.ID
+	}
+
+	alice, bob, claire := ids["Alice"], ids["Bob"], ids["Claire"]
+
+	// games are stored in a different order than they were played
+	for i := 9; i >= 0; i-- {
+		players := []string{alice, bob}
+		if i%2 == 1 {
+			players = []string{alice, claire}
+		}
+
+		require.NoError(t, ds.CreateGameStats(&GameStats{
+			ID:       fmt.Sprintf("game-%d", i),
+			GameType: config.GameType501,
+			Players:  players,
+			Ranks:    Ranks{1: players[0], 2: players[1]},
+			Start:    start.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	gameIDs := func(games []*GameStats) []string {
+		var res []string
+		for _, g := range games {
+			res = append(res, g.ID)
+		}
+		return res
+	}
+
+	t.Run("pages are ordered by start time", func(t *testing.T) {
+		page, err := ds.ListGameStatsPage("", 4)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"game-0", "game-1", "game-2", "game-3"}, gameIDs(page.Games))
+		require.NotEmpty(t, page.Next)
+
+		page, err = ds.ListGameStatsPage(page.Next, 4)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"game-4", "game-5", "game-6", "game-7"}, gameIDs(page.Games))
+		require.NotEmpty(t, page.Next)
+
+		page, err = ds.ListGameStatsPage(page.Next, 4)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"game-8", "game-9"}, gameIDs(page.Games))
+		assert.Empty(t, page.Next)
+	})
+
+	t.Run("last page is detected when it is full", func(t *testing.T) {
+		page, err := ds.ListGameStatsPage("", 10)
+		require.NoError(t, err)
+		assert.Len(t, page.Games, 10)
+		assert.Empty(t, page.Next)
+	})
+
+	t.Run("filters are applied to pages", func(t *testing.T) {
+		page, err := ds.ListGameStatsPage("", 2, PlayerFilter(claire), DateRangeFilter(start.Add(2*time.Hour), start.Add(8*time.Hour)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"game-3", "game-5"}, gameIDs(page.Games))
+		require.NotEmpty(t, page.Next)
+
+		page, err = ds.ListGameStatsPage(page.Next, 2, PlayerFilter(claire), DateRangeFilter(start.Add(2*time.Hour), start.Add(8*time.Hour)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"game-7"}, gameIDs(page.Games))
+		assert.Empty(t, page.Next)
+	})
+
+	t.Run("games are counted by player", func(t *testing.T) {
+		counts, err := ds.CountGamesByPlayer()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{alice: 10, bob: 5, claire: 5}, counts)
+	})
+
+	t.Run("indexes follow deletions and merges", func(t *testing.T) {
+		require.NoError(t, ds.DeleteGameStats("game-3"))
+		require.NoError(t, ds.MergePlayerProfiles(claire, bob))
+
+		counts, err := ds.CountGamesByPlayer()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{alice: 9, bob: 9}, counts)
+
+		games, err := ds.ListGameStats(PlayerFilter(claire))
+		require.NoError(t, err)
+		assert.Empty(t, games)
+
+		games, err = ds.ListGameStats(PlayerFilter(bob))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"game-0", "game-1", "game-2", "game-4", "game-5", "game-6", "game-7", "game-8", "game-9"}, gameIDs(games))
+	})
+}
+
+func TestBolt_ListPlayerStats(t *testing.T) {
+	var (
+		ds    = newTestDatastore(t)
+		start = time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	)
+
+	game := func(id string, hour int, winner, loser string, scores ...int) *GameStats {
+		g := &GameStats{
+			ID:       id,
+			GameType: config.GameType501,
+			Checkout: string(checkout.CheckoutTypeDoubleOut),
+			Players:  []string{winner, loser},
+			Ranks:    Ranks{1: winner, 2: loser},
+			Start:    start.Add(time.Duration(hour) * time.Hour),
+		}
+
+		for i, score := range scores {
+			g.Moves = append(g.Moves, Move{
+				Round:    i/2 + 1,
+				Player:   g.Players[i%2],
+				Score:    Score{Total: score, Fields: []string{strconv.Itoa(score)}},
+				Duration: "2s",
+			})
+		}
+
+		return g
+	}
+
+	// compares the stored statistics with statistics computed from all games
+	assertConsistent := func(t *testing.T) {
+		games, err := ds.ListGameStats()
+		require.NoError(t, err)
+
+		want, err := ToPlayerStats(games, PlayerNames{})
+		require.NoError(t, err)
+
+		got, err := ds.ListPlayerStats()
+		require.NoError(t, err)
+
+		byID := func(ps []*PlayerStats) map[string]*PlayerStats {
+			res := map[string]*PlayerStats{}
+			for _, p := range ps {
+				res[p.ID] = p
+			}
+			return res
+		}
+
+		// the form is stored separately
+		for _, p := range want {
+			form, err := ds.GetPlayerForm(p.ID)
+			require.NoError(t, err)
+			assert.Equal(t, p.Form, form)
+
+			p.Form = nil
+		}
+
+		assert.Equal(t, byID(want), byID(got))
+	}
+
+	require.NoError(t, ds.CreateGameStats(game("1", 1, "a", "b", 60, 45, 100, 20)))
+	require.NoError(t, ds.CreateGameStats(game("2", 2, "b", "c", 140, 26)))
+	require.NoError(t, ds.CreateGameStats(game("3", 3, "a", "c", 180, 41, 85)))
+	assertConsistent(t)
+
+	stats, err := ds.ListPlayerStats()
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+
+	t.Run("games added out of order", func(t *testing.T) {
+		require.NoError(t, ds.CreateGameStats(game("4", 0, "c", "a", 26, 60)))
+		assertConsistent(t)
+	})
+
+	t.Run("deleted games", func(t *testing.T) {
+		require.NoError(t, ds.DeleteGameStats("3"))
+		assertConsistent(t)
+	})
+
+	t.Run("rebuild", func(t *testing.T) {
+		require.NoError(t, ds.RebuildPlayerStats())
+		assertConsistent(t)
+	})
+}
@@ -3,6 +3,9 @@ package datastore
 import (
 	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Gerrit91/darts-counter/pkg/checkout"
@@ -12,16 +15,46 @@ import (
 type (
 	Datastore interface {
 		CreateGameStats(g *GameStats) error
+		// DeleteGameStats deletes a game, the statistics of all players are recomputed afterwards
+		// because the ratings depend on the order of the games
 		DeleteGameStats(id string) error
 		ListGameStats(filterOpts ...filter) ([]*GameStats, error)
+		// ListGameStatsPage returns up to limit games that started after the game the cursor points to,
+		// an empty cursor starts with the first game
+		ListGameStatsPage(after string, limit int, filterOpts ...filter) (*GameStatsPage, error)
+		// CountGamesByPlayer returns the number of games of every player who played at least one game
+		CountGamesByPlayer() (map[string]int, error)
+		// ListPlayerStats returns the precomputed statistics of all players over all games without their form
+		ListPlayerStats() ([]*PlayerStats, error)
+		// GetPlayerForm returns the form of a player over all games
+		GetPlayerForm(id string) ([]FormPoint, error)
+		// RebuildPlayerStats recomputes the statistics of all players from the stored games
+		RebuildPlayerStats() error
+		// ImportArchive adds the games of an archive that do not exist yet, players are matched by ID or name
+		ImportArchive(a *Archive) (*ImportResult, error)
+		CreateMatchStats(m *MatchStats) error
+		ListMatchStats() ([]*MatchStats, error)
 		GetGameSettings() (*GameSettings, error)
 		UpdateGameSettings(s *GameSettings) error
+		ListPlayerProfiles() ([]*PlayerProfile, error)
+		UpdatePlayerProfile(p *PlayerProfile) error
+		MergePlayerProfiles(fromID, intoID string) error
+		GetCheckpoint() (*Checkpoint, error)
+		UpdateCheckpoint(c *Checkpoint) error
+		DeleteCheckpoint() error
 		Close()
 	}
 
 	filter   any
 	idFilter struct{ id string }
 
+	// GameStatsPage contains games ordered by start time, Next is the cursor of the following page
+	// and empty when there are no more games
+	GameStatsPage struct {
+		Games []*GameStats
+		Next  string
+	}
+
 	GameStats struct {
 		ID       string          `json:"id"`
 		GameType config.GameType `json:"type"`
@@ -33,16 +66,47 @@ type (
 		Start    time.Time       `json:"start"`
 		End      time.Time       `json:"end"`
 		Moves    []Move          `json:"moves"`
+		MatchID  string          `json:"match_id,omitempty"`
+		Set      int             `json:"set,omitempty"`
+		Leg      int             `json:"leg,omitempty"`
+
+		StartScores map[string]int `json:"start_scores,omitempty"`
+	}
+
+	MatchStats struct {
+		ID       string          `json:"id"`
+		GameType config.GameType `json:"type"`
+		Sets     int             `json:"sets"`
+		Legs     int             `json:"legs"`
+		Players  []string        `json:"players"`
+		Winner   string          `json:"winner"`
+		SetsWon  map[string]int  `json:"sets_won"`
+		LegsWon  map[string]int  `json:"legs_won"`
+		Results  []LegResult     `json:"results"`
+		Start    time.Time       `json:"start"`
+		End      time.Time       `json:"end"`
+	}
+
+	LegResult struct {
+		GameID string `json:"game_id"`
+		Set    int    `json:"set"`
+		Leg    int    `json:"leg"`
+		Winner string `json:"winner"`
 	}
 
 	Ranks map[int]string
 
+	// Move is the turn of a player. Darts is the number of darts thrown in the turn, games that were
+	// recorded before it was tracked for x01 games only have it for around the clock.
 	Move struct {
 		Round     int    `json:"round"`
 		Player    string `json:"player"`
 		Score     Score  `json:"score"`
 		Remaining int    `json:"remaining"`
 		Duration  string `json:"duration"`
+		Marks     int    `json:"marks,omitempty"`
+		Darts     int    `json:"darts,omitempty"`
+		Bust      bool   `json:"bust,omitempty"`
 	}
 
 	Score struct {
@@ -56,10 +120,34 @@ type (
 		Checkin         checkout.CheckinType  `json:"checkin"`
 		Players         []Player              `json:"players"`
 		SaveGameToStats bool                  `json:"save_game_to_stats"`
+		Sets            int                   `json:"sets,omitempty"`
+		Legs            int                   `json:"legs,omitempty"`
+		SkipAhead       bool                  `json:"skip_ahead,omitempty"`
+		PerDartInput    bool                  `json:"per_dart_input,omitempty"`
+		StartScore      int                   `json:"start_score,omitempty"`
+	}
+
+	// Checkpoint is the state of a running game, it is stored after every move so that the game can be resumed
+	Checkpoint struct {
+		Settings   GameSettings `json:"settings"`
+		GameID     string       `json:"game_id"`
+		Start      time.Time    `json:"start"`
+		Moves      []Move       `json:"moves"`
+		Round      int          `json:"round"`
+		Player     string       `json:"player,omitempty"`
+		Ranks      Ranks        `json:"ranks,omitempty"`
+		MatchID    string       `json:"match_id,omitempty"`
+		MatchStart time.Time    `json:"match_start"`
+		Results    []LegResult  `json:"results,omitempty"`
+		Updated    time.Time    `json:"updated"`
 	}
 
 	Player struct {
+		// ID references the player profile, it is assigned when the settings are stored
+		ID   string `json:"id,omitempty"`
 		Name string `json:"name"`
+		// StartScore overrides the start score of the game for this player, e.g. for handicap games
+		StartScore int `json:"start_score,omitempty"`
 	}
 )
 
@@ -96,28 +184,81 @@ func (r Ranks) OfPlayer(id string) int {
 	return 0
 }
 
+// StartScoreOf returns the score a player started the game with, games that were recorded
+// before start scores were stored fall back to the score of the game type
+func (g *GameStats) StartScoreOf(player string) int {
+	if score, ok := g.StartScores[player]; ok {
+		return score
+	}
+
+	score, _ := strconv.Atoi(string(g.GameType))
+
+	return score
+}
+
+// GameName returns the game type, for custom and handicap games the actual start scores are returned instead
+func (g *GameStats) GameName() string {
+	var scores []string
+	for _, p := range g.Players {
+		score := strconv.Itoa(g.StartScoreOf(p))
+		if !slices.Contains(scores, score) {
+			scores = append(scores, score)
+		}
+	}
+
+	switch {
+	case len(scores) > 1:
+		return strings.Join(scores, "/")
+	case len(scores) == 1 && g.GameType == config.GameTypeCustom:
+		return scores[0]
+	default:
+		return string(g.GameType)
+	}
+}
+
 func validateGameSettings(g *GameSettings) error {
 	switch gt := g.Type; gt {
 	case config.GameType101, config.GameType301, config.GameType501, config.GameType701, config.GameType1001:
 		// noop
+	case config.GameTypeCustom:
+		if g.StartScore <= 0 {
+			return fmt.Errorf("custom games require a start score greater than zero")
+		}
+	case config.GameTypeAroundTheClock:
+		// noop
+	case config.GameTypeCricket:
+		if g.Sets > 1 || g.Legs > 1 {
+			return fmt.Errorf("sets and legs are not supported for cricket games")
+		}
 	default:
 		return fmt.Errorf("unknown game type: %s", gt)
 	}
 
 	switch g.Checkin {
-	case checkout.CheckinTypeDoubleIn, checkout.CheckinTypeStraightIn:
+	case checkout.CheckinTypeDoubleIn, checkout.CheckinTypeStraightIn, checkout.CheckinTypeMasterIn:
 		// noop
 	default:
 		return fmt.Errorf("unknown check-in type: %s", g.Checkin)
 	}
 
 	switch g.Checkout {
-	case checkout.CheckoutTypeDoubleOut, checkout.CheckoutTypeStraightOut:
+	case checkout.CheckoutTypeDoubleOut, checkout.CheckoutTypeStraightOut, checkout.CheckoutTypeMasterOut:
 		// noop
 	default:
 		return fmt.Errorf("unknown check-out type: %s", g.Checkout)
 	}
 
+	// a score of 1 can never be finished when the last dart has to be a double or triple
+	if g.Type == config.GameTypeCustom && g.StartScore == 1 && g.Checkout.LeavesOneBust() {
+		return fmt.Errorf("a start score of 1 cannot be finished with %s", g.Checkout)
+	}
+
+	for _, bestOf := range []int{g.Sets, g.Legs} {
+		if bestOf < 0 || (bestOf > 0 && bestOf%2 == 0) {
+			return fmt.Errorf("sets and legs must be played as best of an odd number")
+		}
+	}
+
 	if len(g.Players) < 1 {
 		return fmt.Errorf("a game needs at least one player")
 	}
@@ -129,6 +270,14 @@ func validateGameSettings(g *GameSettings) error {
 			return fmt.Errorf("player names must be unique")
 		}
 
+		if p.StartScore < 0 {
+			return fmt.Errorf("start score of player %s must not be negative", p.Name)
+		}
+
+		if p.StartScore == 1 && g.Checkout.LeavesOneBust() && g.Type != config.GameTypeCricket && g.Type != config.GameTypeAroundTheClock {
+			return fmt.Errorf("start score of player %s cannot be finished with %s", p.Name, g.Checkout)
+		}
+
 		names[p.Name] = true
 	}
 
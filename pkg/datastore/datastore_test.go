@@ -0,0 +1,69 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGameSettings_StartScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *GameSettings
+		wantErr  string
+	}{
+		{
+			name: "start score of 1 with double-out",
+			settings: &GameSettings{
+				Type:       config.GameTypeCustom,
+				StartScore: 1,
+				Checkout:   checkout.CheckoutTypeDoubleOut,
+				Checkin:    checkout.CheckinTypeStraightIn,
+				Players:    []Player{{Name: "Alice"}},
+			},
+			wantErr: "a start score of 1 cannot be finished with double-out",
+		},
+		{
+			name: "start score of 1 with straight-out",
+			settings: &GameSettings{
+				Type:       config.GameTypeCustom,
+				StartScore: 1,
+				Checkout:   checkout.CheckoutTypeStraightOut,
+				Checkin:    checkout.CheckinTypeStraightIn,
+				Players:    []Player{{Name: "Alice"}},
+			},
+		},
+		{
+			name: "player start score of 1 with master-out",
+			settings: &GameSettings{
+				Type:     config.GameType501,
+				Checkout: checkout.CheckoutTypeMasterOut,
+				Checkin:  checkout.CheckinTypeStraightIn,
+				Players:  []Player{{Name: "Alice"}, {Name: "Bob", StartScore: 1}},
+			},
+			wantErr: "start score of player Bob cannot be finished with master-out",
+		},
+		{
+			name: "player start score of 2 with double-out",
+			settings: &GameSettings{
+				Type:     config.GameType501,
+				Checkout: checkout.CheckoutTypeDoubleOut,
+				Checkin:  checkout.CheckinTypeStraightIn,
+				Players:  []Player{{Name: "Alice"}, {Name: "Bob", StartScore: 2}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGameSettings(tt.settings)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
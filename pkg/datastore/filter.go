@@ -0,0 +1,163 @@
+package datastore
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+)
+
+type (
+	// gameMatcher is implemented by all filters except the id filter, multiple filters are combined with a logical and
+	gameMatcher interface {
+		matches(g *GameStats) bool
+	}
+
+	playerFilter    struct{ id string }
+	gameTypeFilter  struct{ t config.GameType }
+	checkoutFilter  struct{ t checkout.CheckoutType }
+	checkinFilter   struct{ t checkout.CheckinType }
+	dateRangeFilter struct{ from, to time.Time }
+	finishedFilter  struct{ finished bool }
+)
+
+// PlayerFilter returns games in which the player with the given ID took part
+func PlayerFilter(id string) filter {
+	return &playerFilter{id: id}
+}
+
+func GameTypeFilter(t config.GameType) filter {
+	return &gameTypeFilter{t: t}
+}
+
+func CheckoutFilter(t checkout.CheckoutType) filter {
+	return &checkoutFilter{t: t}
+}
+
+func CheckinFilter(t checkout.CheckinType) filter {
+	return &checkinFilter{t: t}
+}
+
+// DateRangeFilter returns games that started within the given range, a zero time leaves the range open on that side
+func DateRangeFilter(from, to time.Time) filter {
+	return &dateRangeFilter{from: from, to: to}
+}
+
+// FinishedFilter returns either finished or unfinished games, a game is finished when it has ranks
+func FinishedFilter(finished bool) filter {
+	return &finishedFilter{finished: finished}
+}
+
+func matchesAll(g *GameStats, matchers []gameMatcher) bool {
+	for _, m := range matchers {
+		if !m.matches(g) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *playerFilter) matches(g *GameStats) bool {
+	return slices.Contains(g.Players, f.id)
+}
+
+func (f *gameTypeFilter) matches(g *GameStats) bool {
+	return g.GameType == f.t
+}
+
+func (f *checkoutFilter) matches(g *GameStats) bool {
+	return g.Checkout == string(f.t)
+}
+
+func (f *checkinFilter) matches(g *GameStats) bool {
+	return g.Checkin == string(f.t)
+}
+
+func (f *dateRangeFilter) matches(g *GameStats) bool {
+	if !f.from.IsZero() && g.Start.Before(f.from) {
+		return false
+	}
+
+	if !f.to.IsZero() && !g.Start.Before(f.to) {
+		return false
+	}
+
+	return true
+}
+
+func (f *finishedFilter) matches(g *GameStats) bool {
+	return (len(g.Ranks) > 0) == f.finished
+}
+
+// ParseFilters parses a filter query of space separated key:value pairs, e.g. "player:Alice type:501 days:30".
+// Supported keys are player, type, out, in, days, from, to (as YYYY-MM-DD) and finished (yes or no).
+func ParseFilters(query string, profiles []*PlayerProfile, now time.Time) ([]filter, error) {
+	var filters []filter
+
+	for _, term := range strings.Fields(query) {
+		k, v, ok := strings.Cut(term, ":")
+		if !ok || v == "" {
+			return nil, fmt.Errorf("filter %q must be given as key:value", term)
+		}
+
+		k = strings.ToLower(k)
+
+		switch k {
+		case "player":
+			idx := slices.IndexFunc(profiles, func(p *PlayerProfile) bool {
+				return strings.EqualFold(p.Name, v) || strings.EqualFold(p.Nickname, v)
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("%w: player %q", ErrNotFound, v)
+			}
+
+			filters = append(filters, PlayerFilter(profiles[idx].ID))
+		case "type":
+			filters = append(filters, GameTypeFilter(config.GameType(strings.ToLower(v))))
+		case "out":
+			// allow the short form, e.g. out:double
+			v = strings.TrimSuffix(strings.ToLower(v), "-out") + "-out"
+			filters = append(filters, CheckoutFilter(checkout.CheckoutType(v)))
+		case "in":
+			v = strings.TrimSuffix(strings.ToLower(v), "-in") + "-in"
+			filters = append(filters, CheckinFilter(checkout.CheckinType(v)))
+		case "days":
+			days, err := strconv.Atoi(v)
+			if err != nil || days <= 0 {
+				return nil, fmt.Errorf("days must be a positive number")
+			}
+
+			filters = append(filters, DateRangeFilter(now.AddDate(0, 0, -days), time.Time{}))
+		case "from", "to":
+			date, err := time.ParseInLocation(time.DateOnly, v, now.Location())
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse date %q, expected YYYY-MM-DD", v)
+			}
+
+			if k == "from" {
+				filters = append(filters, DateRangeFilter(date, time.Time{}))
+			} else {
+				// the end date is inclusive
+				filters = append(filters, DateRangeFilter(time.Time{}, date.AddDate(0, 0, 1)))
+			}
+		case "finished":
+			switch strings.ToLower(v) {
+			case "yes", "true":
+				filters = append(filters, FinishedFilter(true))
+			case "no", "false":
+				filters = append(filters, FinishedFilter(false))
+			default:
+				return nil, fmt.Errorf("finished must be yes or no")
+			}
+		default:
+			return nil, fmt.Errorf("unknown filter %q", k)
+		}
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,129 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilters(t *testing.T) {
+	var (
+		now      = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+		profiles = []*PlayerProfile{
+			{ID: "1", Name: "Alice"},
+			{ID: "2", Name: "Bob", Nickname: "Bobby"},
+		}
+		games = map[string]*GameStats{
+			"recent-501": {
+				GameType: config.GameType501,
+				Checkout: string(checkout.CheckoutTypeDoubleOut),
+				Checkin:  string(checkout.CheckinTypeStraightIn),
+				Players:  []string{"1", "2"},
+				Ranks:    Ranks{1: "1", 2: "2"},
+				Start:    now.AddDate(0, 0, -3),
+			},
+			"old-301": {
+				GameType: config.GameType301,
+				Checkout: string(checkout.CheckoutTypeMasterOut),
+				Checkin:  string(checkout.CheckinTypeDoubleIn),
+				Players:  []string{"1"},
+				Ranks:    Ranks{1: "1"},
+				Start:    now.AddDate(0, -2, 0),
+			},
+			"unfinished-cricket": {
+				GameType: config.GameTypeCricket,
+				Players:  []string{"2"},
+				Start:    now.AddDate(0, 0, -40),
+			},
+		}
+	)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "no filters",
+			query: "",
+			want:  []string{"old-301", "recent-501", "unfinished-cricket"},
+		},
+		{
+			name:  "player by nickname",
+			query: "player:bobby",
+			want:  []string{"recent-501", "unfinished-cricket"},
+		},
+		{
+			name:  "combined",
+			query: "player:Alice out:double",
+			want:  []string{"recent-501"},
+		},
+		{
+			name:  "game type and check-in",
+			query: "type:301 in:double-in",
+			want:  []string{"old-301"},
+		},
+		{
+			name:  "last days",
+			query: "days:30",
+			want:  []string{"recent-501"},
+		},
+		{
+			name:  "date range with inclusive end",
+			query: "from:2026-01-31 to:2026-02-20",
+			want:  []string{"old-301", "unfinished-cricket"},
+		},
+		{
+			name:  "unfinished",
+			query: "finished:no",
+			want:  []string{"unfinished-cricket"},
+		},
+		{
+			name:    "unknown player",
+			query:   "player:Carol",
+			wantErr: `not found: player "Carol"`,
+		},
+		{
+			name:    "unknown key",
+			query:   "winner:Alice",
+			wantErr: `unknown filter "winner"`,
+		},
+		{
+			name:    "missing value",
+			query:   "type",
+			wantErr: `filter "type" must be given as key:value`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := ParseFilters(tt.query, profiles, now)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var matchers []gameMatcher
+			for _, f := range filters {
+				m, ok := f.(gameMatcher)
+				require.True(t, ok)
+				matchers = append(matchers, m)
+			}
+
+			var got []string
+			for _, name := range []string{"old-301", "recent-501", "unfinished-cricket"} {
+				if matchesAll(games[name], matchers) {
+					got = append(got, name)
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
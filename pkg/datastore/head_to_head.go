@@ -0,0 +1,64 @@
+package datastore
+
+import (
+	"fmt"
+	"slices"
+)
+
+type (
+	// HeadToHead compares two players in the games they played against each other
+	HeadToHead struct {
+		// Players contains the statistics of both players, taken only from the shared games
+		Players [2]*PlayerStats
+		Wins    [2]int
+		Games   []*GameStats
+	}
+)
+
+func ToHeadToHead(stats []*GameStats, names PlayerNames, a, b string) (*HeadToHead, error) {
+	if a == b {
+		return nil, fmt.Errorf("cannot compare a player with itself")
+	}
+
+	h := &HeadToHead{}
+
+	for _, g := range stats {
+		if !slices.Contains(g.Players, a) || !slices.Contains(g.Players, b) {
+			continue
+		}
+
+		h.Games = append(h.Games, g)
+
+		rankA, rankB := g.Ranks.OfPlayer(a), g.Ranks.OfPlayer(b)
+		switch {
+		case rankA == 0 || rankB == 0:
+			// game was not finished
+		case rankA < rankB:
+			h.Wins[0]++
+		case rankB < rankA:
+			h.Wins[1]++
+		}
+	}
+
+	ps, err := ToPlayerStats(h.Games, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range []string{a, b} {
+		h.Players[i] = &PlayerStats{
+			ID:          id,
+			Name:        names.Of(id),
+			RanksCount:  map[int]int{},
+			FieldsCount: map[string]int{},
+		}
+
+		for _, p := range ps {
+			if p.ID == id {
+				h.Players[i] = p
+			}
+		}
+	}
+
+	return h, nil
+}
@@ -2,12 +2,17 @@ package datastore
 
 import (
 	"fmt"
+	"sort"
 	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
 )
 
 type (
 	PlayerStats struct {
 		ID              string
+		Name            string
 		GamesPlayed     int
 		RanksCount      map[int]int
 		AverageRank     float64
@@ -18,72 +23,324 @@ type (
 		HighestScore    Score
 		TotalScore      int
 		AverageScore    float64
+		Busts           int
+		TotalMarks      int
+		MarksPerRound   float64
+
+		ThreeDartAverage   float64
+		FirstNineAverage   float64
+		CheckoutPercentage float64
+		Checkouts          int
+		CheckoutDarts      int
+		HighestCheckout    int
+		BestLeg            int
+		Scores100          int
+		Scores140          int
+		Scores180          int
+
+		// Rating is the current ELO-style rating, Form contains the state after every game in chronological order
+		Rating float64
+		Form   []FormPoint
+
+		AroundTheClockRuns     int
+		AroundTheClockBestRun  int
+		AroundTheClockAvgDarts float64
+
+		totals playerTotals
+	}
+
+	// playerTotals are the running totals from which the averages of the player statistics are derived
+	playerTotals struct {
+		Ranks               int `json:"ranks"`
+		ScoreMoves          int `json:"score_moves"`
+		ScoreDarts          int `json:"score_darts"`
+		FirstNineScore      int `json:"first_nine_score"`
+		FirstNineDarts      int `json:"first_nine_darts"`
+		CricketMoves        int `json:"cricket_moves"`
+		AroundTheClockDarts int `json:"around_the_clock_darts"`
 
-		totalRanks int
+		// RecentScores and RecentDarts contain the x01 scores and darts of the last games for the form
+		RecentScores []int `json:"recent_scores,omitempty"`
+		RecentDarts  []int `json:"recent_darts,omitempty"`
 	}
 )
 
-func ToPlayerStats(stats []*GameStats) ([]*PlayerStats, error) {
+func ToPlayerStats(stats []*GameStats, names PlayerNames) ([]*PlayerStats, error) {
+	games := append([]*GameStats{}, stats...)
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].Start.Before(games[j].Start)
+	})
+
 	playerMap := map[string]*PlayerStats{}
 
-	for _, s := range stats {
-		for _, id := range s.Players {
-			p, ok := playerMap[id]
-			if !ok {
-				p = &PlayerStats{
-					ID:          id,
-					RanksCount:  map[int]int{},
-					FieldsCount: map[string]int{},
-				}
+	for _, s := range games {
+		err := addGame(playerMap, s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ps []*PlayerStats
+	for _, p := range playerMap {
+		p.Name = names.Of(p.ID)
+		p.finalize()
+
+		ps = append(ps, p)
+	}
+
+	return ps, nil
+}
+
+func newPlayerStats(id string) *PlayerStats {
+	return &PlayerStats{
+		ID:          id,
+		RanksCount:  map[int]int{},
+		FieldsCount: map[string]int{},
+		Rating:      InitialRating,
+	}
+}
+
+// addGame adds a game to the statistics of its players, missing players are added to the map.
+// Games have to be added in chronological order because of the rating.
+func addGame(playerMap map[string]*PlayerStats, s *GameStats) error {
+	for _, id := range s.Players {
+		p, ok := playerMap[id]
+		if !ok {
+			p = newPlayerStats(id)
+		}
+
+		var (
+			darts    int
+			finished bool
+			turns    int
+		)
+
+		for _, move := range s.Moves {
+			if move.Player != id {
+				continue
 			}
 
-			for _, move := range s.Moves {
-				if move.Player != id {
-					continue
-				}
+			duration, err := time.ParseDuration(move.Duration)
+			if err != nil {
+				return fmt.Errorf("unable to parse move duration: %w", err)
+			}
 
-				duration, err := time.ParseDuration(move.Duration)
-				if err != nil {
-					return nil, fmt.Errorf("unable to parse move duration: %w", err)
-				}
+			for _, field := range move.Score.Fields {
+				p.FieldsCount[field]++
+			}
+			p.TotalDuration += duration
+			p.TotalMoves++
 
-				for _, field := range move.Score.Fields {
-					p.FieldsCount[field]++
-				}
-				if p.HighestScore.Total < move.Score.Total {
-					p.HighestScore = move.Score
+			switch s.GameType {
+			case config.GameTypeCricket:
+				// cricket points are not comparable to x01 scores
+				p.TotalMarks += move.Marks
+				p.totals.CricketMoves++
+				continue
+			case config.GameTypeAroundTheClock:
+				darts += move.Darts
+				finished = move.Remaining == 0
+				continue
+			}
+
+			if move.Bust {
+				p.Busts++
+			}
+			if p.HighestScore.Total < move.Score.Total {
+				p.HighestScore = move.Score
+			}
+			p.TotalScore += move.Score.Total
+			p.totals.ScoreMoves++
+
+			thrown := move.thrownDarts()
+			p.totals.ScoreDarts += thrown
+			darts += thrown
+
+			turns++
+			if turns <= 3 {
+				p.totals.FirstNineScore += move.Score.Total
+				p.totals.FirstNineDarts += thrown
+			}
+
+			switch total := move.Score.Total; {
+			case total == 180:
+				p.Scores180++
+			case total >= 140:
+				p.Scores140++
+			case total >= 100:
+				p.Scores100++
+			}
+
+			p.CheckoutDarts += move.checkoutDarts(checkout.CheckoutType(s.Checkout))
+
+			if move.checkedOut() {
+				finished = true
+
+				p.Checkouts++
+				if move.Score.Total > p.HighestCheckout {
+					p.HighestCheckout = move.Score.Total
 				}
-				p.TotalScore += move.Score.Total
-				p.TotalDuration += duration
-				p.TotalMoves++
 			}
+		}
 
-			for rank := range s.Ranks {
-				// create entries in the ranks count
-				p.RanksCount[rank] += 0
+		if finished && isX01(s) {
+			if p.BestLeg == 0 || darts < p.BestLeg {
+				p.BestLeg = darts
 			}
+		}
 
-			p.GamesPlayed++
+		if finished && s.GameType == config.GameTypeAroundTheClock {
+			if p.AroundTheClockBestRun == 0 || darts < p.AroundTheClockBestRun {
+				p.AroundTheClockBestRun = darts
+			}
+			p.AroundTheClockRuns++
+			p.totals.AroundTheClockDarts += darts
+		}
 
-			playerMap[id] = p
+		for rank := range s.Ranks {
+			// create entries in the ranks count
+			p.RanksCount[rank] += 0
 		}
 
-		for rank, player := range s.Ranks {
-			p := playerMap[player]
+		p.GamesPlayed++
+
+		playerMap[id] = p
+	}
 
-			p.RanksCount[rank] += 1
-			p.totalRanks += rank
+	for rank, player := range s.Ranks {
+		p, ok := playerMap[player]
+		if !ok {
+			continue
 		}
+
+		p.RanksCount[rank] += 1
+		p.totals.Ranks += rank
 	}
 
-	var ps []*PlayerStats
-	for _, p := range playerMap {
+	rate(s, playerMap)
+
+	return nil
+}
+
+// finalize derives the averages from the totals
+func (p *PlayerStats) finalize() {
+	if p.TotalMoves > 0 {
 		p.AverageDuration = time.Duration(int64(p.TotalDuration) / int64(p.TotalMoves))
-		p.AverageScore = float64(p.TotalScore) / float64(p.TotalMoves)
-		p.AverageRank = float64(p.totalRanks) / float64(p.GamesPlayed)
+	}
+	if p.totals.ScoreMoves > 0 {
+		p.AverageScore = float64(p.TotalScore) / float64(p.totals.ScoreMoves)
+	}
+	if p.totals.ScoreDarts > 0 {
+		p.ThreeDartAverage = float64(p.TotalScore) / float64(p.totals.ScoreDarts) * 3
+	}
+	if p.totals.FirstNineDarts > 0 {
+		p.FirstNineAverage = float64(p.totals.FirstNineScore) / float64(p.totals.FirstNineDarts) * 3
+	}
+	if p.CheckoutDarts > 0 {
+		p.CheckoutPercentage = float64(p.Checkouts) / float64(p.CheckoutDarts) * 100
+	}
+	if p.totals.CricketMoves > 0 {
+		p.MarksPerRound = float64(p.TotalMarks) / float64(p.totals.CricketMoves)
+	}
+	if p.AroundTheClockRuns > 0 {
+		p.AroundTheClockAvgDarts = float64(p.totals.AroundTheClockDarts) / float64(p.AroundTheClockRuns)
+	}
+	if p.GamesPlayed > 0 {
+		p.AverageRank = float64(p.totals.Ranks) / float64(p.GamesPlayed)
+	}
+}
 
-		ps = append(ps, p)
+// LegDarts returns the darts a player needed to check out in an x01 game, zero if the player did not check out
+func (g *GameStats) LegDarts(player string) int {
+	var (
+		darts    int
+		finished bool
+	)
+
+	for _, move := range g.Moves {
+		if move.Player != player {
+			continue
+		}
+
+		darts += move.thrownDarts()
+		finished = finished || move.checkedOut()
 	}
 
-	return ps, nil
+	if !finished {
+		return 0
+	}
+
+	return darts
+}
+
+func isX01(g *GameStats) bool {
+	return g.GameType != config.GameTypeCricket && g.GameType != config.GameTypeAroundTheClock
+}
+
+// checkedOut returns whether the player finished the game with this move
+func (m *Move) checkedOut() bool {
+	return m.Remaining == 0 && !m.Bust && m.Score.Total > 0
+}
+
+// thrownDarts returns the number of darts of an x01 move, moves that were recorded without
+// darts count three darts unless the fields of the checkout are known
+func (m *Move) thrownDarts() int {
+	if m.Darts > 0 {
+		return m.Darts
+	}
+
+	if m.checkedOut() && len(m.Score.Fields) > 0 {
+		return len(m.Score.Fields)
+	}
+
+	return 3
+}
+
+// checkoutDarts returns the number of darts that were thrown while a single dart could have finished the game
+func (m *Move) checkoutDarts(out checkout.CheckoutType) int {
+	if len(m.Score.Fields) == 0 && m.checkedOut() {
+		// the fields of a checkout entered as total are unknown, but at least the last dart was at a finish
+		return 1
+	}
+
+	var (
+		count     = 0
+		remaining = m.Remaining + m.Score.Total
+	)
+
+	for _, field := range m.Score.Fields {
+		if remaining <= 1 {
+			break
+		}
+
+		score, err := checkout.ParseScore(field)
+		if err != nil {
+			break
+		}
+
+		if isOneDartFinish(remaining, out) {
+			count++
+		}
+
+		remaining -= score.Value()
+	}
+
+	return count
+}
+
+func isOneDartFinish(remaining int, out checkout.CheckoutType) bool {
+	for _, m := range []checkout.Multiplier{checkout.None, checkout.Double, checkout.Triple} {
+		for _, score := range checkout.Singles() {
+			if m == checkout.Triple && score.Value() == checkout.BullsEye {
+				continue
+			}
+
+			score = score.WithMultiplier(m)
+			if score.Value() == remaining && out.IsFinish(score) {
+				return true
+			}
+		}
+	}
+
+	return false
 }
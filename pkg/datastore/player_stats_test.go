@@ -0,0 +1,182 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToPlayerStats_X01(t *testing.T) {
+	move := func(player string, remaining, total int, bust bool, fields ...string) Move {
+		return Move{
+			Player:    player,
+			Score:     Score{Total: total, Fields: fields},
+			Remaining: remaining,
+			Duration:  "1s",
+			Bust:      bust,
+		}
+	}
+
+	games := []*GameStats{
+		{
+			GameType: config.GameType301,
+			Checkout: string(checkout.CheckoutTypeDoubleOut),
+			Players:  []string{"a", "b"},
+			Ranks:    Ranks{1: "a", 2: "b"},
+			Moves: []Move{
+				move("a", 121, 180, false, "T20", "T20", "T20"),
+				move("b", 201, 100, false, "T20", "20", "20"),
+				move("a", 40, 81, false, "T20", "1", "D10"),
+				move("b", 61, 140, false, "T20", "T20", "20"),
+				move("a", 40, 0, true, "T20"),
+				move("b", 61, 0, false, "M", "M", "M"),
+				move("a", 0, 40, false, "D10", "D10"),
+			},
+		},
+	}
+
+	stats, err := ToPlayerStats(games, PlayerNames{"a": "Alice"})
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	var a, b *PlayerStats
+	for _, ps := range stats {
+		switch ps.ID {
+		case "a":
+			a = ps
+		case "b":
+			b = ps
+		}
+	}
+
+	assert.Equal(t, "Alice", a.Name)
+	assert.Equal(t, "b", b.Name)
+
+	// 301 points with 11 darts
+	assert.InDelta(t, 82.09, a.ThreeDartAverage, 0.01)
+	assert.InDelta(t, 87.0, a.FirstNineAverage, 0.01)
+	assert.Equal(t, 1, a.Scores180)
+	assert.Equal(t, 0, a.Scores140)
+	// the busted turn has one dart at 40, the checkout turn has darts at 40 and 20
+	assert.Equal(t, 3, a.CheckoutDarts)
+	assert.Equal(t, 1, a.Checkouts)
+	assert.InDelta(t, 33.33, a.CheckoutPercentage, 0.01)
+	assert.Equal(t, 40, a.HighestCheckout)
+	assert.Equal(t, 11, a.BestLeg)
+
+	assert.InDelta(t, 80.0, b.ThreeDartAverage, 0.01)
+	assert.Equal(t, 1, b.Scores100)
+	assert.Equal(t, 1, b.Scores140)
+	assert.Equal(t, 0, b.CheckoutDarts)
+	assert.Equal(t, 0, b.BestLeg)
+}
+
+func TestToPlayerStats_CheckoutAsTotal(t *testing.T) {
+	games := []*GameStats{
+		{
+			GameType: config.GameType101,
+			Checkout: string(checkout.CheckoutTypeDoubleOut),
+			Players:  []string{"a"},
+			Ranks:    Ranks{1: "a"},
+			Moves: []Move{
+				{Player: "a", Score: Score{Total: 61, Fields: []string{"T20", "1", "M"}}, Remaining: 40, Duration: "1s"},
+				{Player: "a", Score: Score{Total: 40}, Remaining: 0, Duration: "1s"},
+			},
+		},
+		{
+			GameType: config.GameType101,
+			Checkout: string(checkout.CheckoutTypeDoubleOut),
+			Players:  []string{"a"},
+			Ranks:    Ranks{1: "a"},
+			Moves: []Move{
+				{Player: "a", Score: Score{Total: 61, Fields: []string{"T20", "1", "M"}}, Remaining: 40, Duration: "1s"},
+				{Player: "a", Score: Score{Total: 40, Fields: []string{"D20"}}, Remaining: 0, Duration: "1s"},
+			},
+		},
+	}
+
+	stats, err := ToPlayerStats(games, PlayerNames{})
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+
+	// both games have a missed dart at 40, the checkout entered as total counts one dart at a finish
+	assert.Equal(t, 2, stats[0].Checkouts)
+	assert.Equal(t, 4, stats[0].CheckoutDarts)
+	assert.InDelta(t, 50.0, stats[0].CheckoutPercentage, 0.01)
+}
+
+func TestGameStats_LegDarts(t *testing.T) {
+	gs := &GameStats{
+		GameType: config.GameType101,
+		Players:  []string{"a", "b"},
+		Moves: []Move{
+			{Player: "a", Score: Score{Total: 60, Fields: []string{"T20", "M", "M"}}, Remaining: 41, Duration: "1s"},
+			{Player: "b", Score: Score{Total: 45}, Remaining: 56, Duration: "1s"},
+			{Player: "a", Score: Score{Total: 41}, Remaining: 0, Duration: "1s", Darts: 2},
+		},
+	}
+
+	assert.Equal(t, 5, gs.LegDarts("a"))
+	assert.Equal(t, 0, gs.LegDarts("b"))
+}
+
+func TestToPlayerStats_Rating(t *testing.T) {
+	var (
+		now  = time.Now()
+		game = func(start time.Time, ranks Ranks) *GameStats {
+			return &GameStats{
+				GameType: config.GameTypeCricket,
+				Players:  []string{"a", "b", "c"},
+				Ranks:    ranks,
+				Start:    start,
+			}
+		}
+	)
+
+	// the games are rated in chronological order regardless of the given order
+	stats, err := ToPlayerStats([]*GameStats{
+		game(now.Add(time.Hour), Ranks{1: "a", 2: "c", 3: "b"}),
+		game(now, Ranks{1: "a", 2: "b", 3: "c"}),
+	}, PlayerNames{})
+	require.NoError(t, err)
+
+	ratings := map[string]float64{}
+	total := 0.0
+	for _, ps := range stats {
+		require.Len(t, ps.Form, 2)
+		assert.Equal(t, ps.Rating, ps.Form[1].Rating)
+		ratings[ps.ID] = ps.Rating
+		total += ps.Rating
+	}
+
+	assert.InDelta(t, 3*InitialRating, total, 0.001)
+	assert.Greater(t, ratings["a"], InitialRating)
+	assert.Less(t, ratings["b"], InitialRating)
+	assert.Less(t, ratings["c"], InitialRating)
+}
+
+func TestToHeadToHead(t *testing.T) {
+	games := []*GameStats{
+		{ID: "1", GameType: config.GameTypeCricket, Players: []string{"a", "b"}, Ranks: Ranks{1: "a", 2: "b"}},
+		{ID: "2", GameType: config.GameTypeCricket, Players: []string{"a", "c", "b"}, Ranks: Ranks{1: "c", 2: "b", 3: "a"}},
+		{ID: "3", GameType: config.GameTypeCricket, Players: []string{"a", "c"}, Ranks: Ranks{1: "a", 2: "c"}},
+		{ID: "4", GameType: config.GameTypeCricket, Players: []string{"b", "a"}, Ranks: Ranks{1: "a", 2: "b"}},
+	}
+
+	h, err := ToHeadToHead(games, PlayerNames{"a": "Alice", "b": "Bob"}, "a", "b")
+	require.NoError(t, err)
+
+	require.Len(t, h.Games, 3)
+	assert.Equal(t, [2]int{2, 1}, h.Wins)
+	assert.Equal(t, "Alice", h.Players[0].Name)
+	assert.Equal(t, "Bob", h.Players[1].Name)
+	assert.Equal(t, 3, h.Players[0].GamesPlayed)
+
+	_, err = ToHeadToHead(games, PlayerNames{}, "a", "a")
+	require.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package datastore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// PlayerProfile is a player in the registry, games reference players by the profile ID
+	PlayerProfile struct {
+		ID       string    `json:"id"`
+		Name     string    `json:"name"`
+		Nickname string    `json:"nickname,omitempty"`
+		Created  time.Time `json:"created"`
+	}
+
+	// PlayerNames maps player IDs to their display names
+	PlayerNames map[string]string
+)
+
+func ToPlayerNames(profiles []*PlayerProfile) PlayerNames {
+	names := PlayerNames{}
+
+	for _, p := range profiles {
+		names[p.ID] = p.Name
+	}
+
+	return names
+}
+
+// Of returns the display name of a player, unknown players are shown by their ID
+func (n PlayerNames) Of(id string) string {
+	if name, ok := n[id]; ok {
+		return name
+	}
+
+	return id
+}
+
+// All returns the display names of the given players
+func (n PlayerNames) All(ids []string) []string {
+	var names []string
+
+	for _, id := range ids {
+		names = append(names, n.Of(id))
+	}
+
+	return names
+}
+
+func validatePlayerProfile(p *PlayerProfile, profiles []*PlayerProfile) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("player name must not be empty")
+	}
+
+	for _, other := range profiles {
+		if other.ID != p.ID && other.Name == p.Name {
+			return fmt.Errorf("player name %q is already taken", p.Name)
+		}
+	}
+
+	return nil
+}
+
+// renamePlayers replaces every player reference in the game and returns whether something changed
+func (g *GameStats) renamePlayers(rename func(id string) string) bool {
+	changed := false
+
+	replace := func(id string) string {
+		renamed := rename(id)
+		if renamed != id {
+			changed = true
+		}
+		return renamed
+	}
+
+	for i, p := range g.Players {
+		g.Players[i] = replace(p)
+	}
+
+	for rank, p := range g.Ranks {
+		g.Ranks[rank] = replace(p)
+	}
+
+	for i := range g.Moves {
+		g.Moves[i].Player = replace(g.Moves[i].Player)
+	}
+
+	if g.StartScores != nil {
+		startScores := map[string]int{}
+		for p, score := range g.StartScores {
+			startScores[replace(p)] = score
+		}
+		g.StartScores = startScores
+	}
+
+	return changed
+}
+
+// renamePlayers replaces every player reference in the match and returns whether something changed
+func (m *MatchStats) renamePlayers(rename func(id string) string) bool {
+	changed := false
+
+	replace := func(id string) string {
+		renamed := rename(id)
+		if renamed != id {
+			changed = true
+		}
+		return renamed
+	}
+
+	replaceKeys := func(won map[string]int) map[string]int {
+		if won == nil {
+			return nil
+		}
+
+		res := map[string]int{}
+		for p, count := range won {
+			res[replace(p)] += count
+		}
+		return res
+	}
+
+	for i, p := range m.Players {
+		m.Players[i] = replace(p)
+	}
+
+	if m.Winner != "" {
+		m.Winner = replace(m.Winner)
+	}
+
+	m.SetsWon = replaceKeys(m.SetsWon)
+	m.LegsWon = replaceKeys(m.LegsWon)
+
+	for i := range m.Results {
+		m.Results[i].Winner = replace(m.Results[i].Winner)
+	}
+
+	return changed
+}
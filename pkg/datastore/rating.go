@@ -0,0 +1,118 @@
+package datastore
+
+import (
+	"math"
+)
+
+const (
+	// InitialRating is the rating of a player without any rated games
+	InitialRating = 1000.0
+
+	ratingK     = 32.0
+	ratingScale = 400.0
+
+	// formWindow is the number of games over which the 3-dart average of the form is taken
+	formWindow = 5
+)
+
+type (
+	// FormPoint is the state of a player after a game
+	FormPoint struct {
+		GameID           string
+		Rating           float64
+		ThreeDartAverage float64
+	}
+)
+
+// rate updates the ratings and the form of the players of a game. Multi-player games are rated
+// as pairwise duels between all players, in which the better ranked player wins. The rating change is
+// divided by the number of opponents, so that games with many players do not weigh more.
+func rate(g *GameStats, players map[string]*PlayerStats) {
+	if len(g.Players) > 1 && len(g.Ranks) == len(g.Players) {
+		deltas := map[string]float64{}
+
+		ratingOf := func(id string) float64 {
+			if p, ok := players[id]; ok {
+				return p.Rating
+			}
+			return InitialRating
+		}
+
+		for _, a := range g.Players {
+			for _, b := range g.Players {
+				if a == b {
+					continue
+				}
+
+				var (
+					expected = 1 / (1 + math.Pow(10, (ratingOf(b)-ratingOf(a))/ratingScale))
+					actual   = 0.0
+				)
+
+				switch rankA, rankB := g.Ranks.OfPlayer(a), g.Ranks.OfPlayer(b); {
+				case rankA < rankB:
+					actual = 1
+				case rankA == rankB:
+					actual = 0.5
+				}
+
+				deltas[a] += ratingK * (actual - expected) / float64(len(g.Players)-1)
+			}
+		}
+
+		for id, delta := range deltas {
+			if p, ok := players[id]; ok {
+				p.Rating += delta
+			}
+		}
+	}
+
+	for _, id := range g.Players {
+		p, ok := players[id]
+		if !ok {
+			continue
+		}
+
+		if isX01(g) {
+			score, thrown := 0, 0
+			for _, move := range g.Moves {
+				if move.Player == id {
+					score += move.Score.Total
+					thrown += move.thrownDarts()
+				}
+			}
+
+			p.totals.RecentScores = lastN(append(p.totals.RecentScores, score), formWindow)
+			p.totals.RecentDarts = lastN(append(p.totals.RecentDarts, thrown), formWindow)
+		}
+
+		p.Form = append(p.Form, FormPoint{
+			GameID:           g.ID,
+			Rating:           p.Rating,
+			ThreeDartAverage: rollingAverage(p.totals.RecentScores, p.totals.RecentDarts),
+		})
+	}
+}
+
+func lastN(values []int, n int) []int {
+	if len(values) <= n {
+		return values
+	}
+	return values[len(values)-n:]
+}
+
+// rollingAverage returns the 3-dart average over the last games
+func rollingAverage(scores, darts []int) float64 {
+	var score, thrown int
+
+	for i := max(0, len(scores)-formWindow); i < len(scores); i++ {
+		score += scores[i]
+		thrown += darts[i]
+	}
+
+	if thrown == 0 {
+		return 0
+	}
+
+	return float64(score) / float64(thrown) * 3
+}
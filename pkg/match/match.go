@@ -0,0 +1,196 @@
+package match
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Match is a sequence of legs grouped into sets. A player wins a set by winning the majority
+	// of the legs of the set and wins the match by winning the majority of the sets.
+	Match struct {
+		players []string
+		sets    int
+		legs    int
+
+		setsWon map[string]int
+		legsWon map[string]int
+
+		set       int
+		leg       int
+		legsTotal int
+		results   []LegResult
+		winner    string
+	}
+
+	LegResult struct {
+		Set    int
+		Leg    int
+		Winner string
+	}
+)
+
+// New creates a match over best of the given sets with best of the given legs per set
+func New(players []string, sets, legs int) *Match {
+	if sets < 1 {
+		sets = 1
+	}
+	if legs < 1 {
+		legs = 1
+	}
+
+	m := &Match{
+		players: players,
+		sets:    sets,
+		legs:    legs,
+		setsWon: map[string]int{},
+		legsWon: map[string]int{},
+		set:     1,
+		leg:     1,
+	}
+
+	return m
+}
+
+// IsSingleLeg returns true if the match consists of only one leg
+func (m *Match) IsSingleLeg() bool {
+	return m.sets == 1 && m.legs == 1
+}
+
+// GetPlayers returns the players of the match in their original order
+func (m *Match) GetPlayers() []string {
+	return m.players
+}
+
+// StartingOrder returns the player order for the current leg, the starting player rotates with every leg
+func (m *Match) StartingOrder() []string {
+	if len(m.players) == 0 {
+		return nil
+	}
+
+	offset := m.legsTotal % len(m.players)
+
+	var ps []string
+	ps = append(ps, m.players[offset:]...)
+	ps = append(ps, m.players[:offset]...)
+
+	return ps
+}
+
+// FinishLeg records the winner of the current leg and advances to the next leg.
+// It returns true when the match has been decided.
+func (m *Match) FinishLeg(winner string) (bool, error) {
+	if m.IsFinished() {
+		return true, fmt.Errorf("match is already finished")
+	}
+
+	known := false
+	for _, p := range m.players {
+		if p == winner {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return false, fmt.Errorf("unknown player %q", winner)
+	}
+
+	m.results = append(m.results, LegResult{
+		Set:    m.set,
+		Leg:    m.leg,
+		Winner: winner,
+	})
+
+	m.legsTotal++
+	m.legsWon[winner]++
+	m.leg++
+
+	if m.legsWon[winner] < target(m.legs) {
+		return false, nil
+	}
+
+	m.setsWon[winner]++
+	m.legsWon = map[string]int{}
+	m.leg = 1
+
+	if m.setsWon[winner] < target(m.sets) {
+		m.set++
+		return false, nil
+	}
+
+	m.winner = winner
+
+	return true, nil
+}
+
+func (m *Match) IsFinished() bool {
+	return m.winner != ""
+}
+
+func (m *Match) GetWinner() string {
+	return m.winner
+}
+
+func (m *Match) GetSet() int {
+	return m.set
+}
+
+func (m *Match) GetLeg() int {
+	return m.leg
+}
+
+func (m *Match) GetSets() int {
+	return m.sets
+}
+
+func (m *Match) GetLegs() int {
+	return m.legs
+}
+
+func (m *Match) SetsWon() map[string]int {
+	return m.setsWon
+}
+
+func (m *Match) LegsWon() map[string]int {
+	return m.legsWon
+}
+
+// TotalLegsWon returns the amount of legs won by each player over all sets
+func (m *Match) TotalLegsWon() map[string]int {
+	won := map[string]int{}
+
+	for _, r := range m.results {
+		won[r.Winner]++
+	}
+
+	return won
+}
+
+func (m *Match) Results() []LegResult {
+	return m.results
+}
+
+// Score returns the set and leg score in the order of the players, e.g. "Sets 1:0, Legs 2:1"
+func (m *Match) Score() string {
+	var (
+		sets []string
+		legs []string
+	)
+
+	for _, p := range m.players {
+		sets = append(sets, strconv.Itoa(m.setsWon[p]))
+		legs = append(legs, strconv.Itoa(m.legsWon[p]))
+	}
+
+	if m.sets == 1 {
+		return fmt.Sprintf("Legs %s", strings.Join(legs, ":"))
+	}
+
+	return fmt.Sprintf("Sets %s, Legs %s", strings.Join(sets, ":"), strings.Join(legs, ":"))
+}
+
+// target returns the amount of wins required for a best-of count
+func target(bestOf int) int {
+	return bestOf/2 + 1
+}
@@ -0,0 +1,63 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch_SingleLeg(t *testing.T) {
+	m := New([]string{"a", "b"}, 1, 1)
+	assert.True(t, m.IsSingleLeg())
+	assert.Equal(t, []string{"a", "b"}, m.StartingOrder())
+
+	finished, err := m.FinishLeg("b")
+	require.NoError(t, err)
+	assert.True(t, finished)
+	assert.Equal(t, "b", m.GetWinner())
+
+	_, err = m.FinishLeg("a")
+	require.Error(t, err)
+}
+
+func TestMatch_SetsAndLegs(t *testing.T) {
+	m := New([]string{"a", "b"}, 3, 3)
+	assert.False(t, m.IsSingleLeg())
+
+	legs := []struct {
+		winner      string
+		wantPlayers []string
+		wantScore   string
+		finished    bool
+	}{
+		{winner: "a", wantPlayers: []string{"a", "b"}, wantScore: "Sets 0:0, Legs 1:0"},
+		{winner: "b", wantPlayers: []string{"b", "a"}, wantScore: "Sets 0:0, Legs 1:1"},
+		{winner: "a", wantPlayers: []string{"a", "b"}, wantScore: "Sets 1:0, Legs 0:0"},
+		{winner: "b", wantPlayers: []string{"b", "a"}, wantScore: "Sets 1:0, Legs 0:1"},
+		{winner: "b", wantPlayers: []string{"a", "b"}, wantScore: "Sets 1:1, Legs 0:0"},
+		{winner: "a", wantPlayers: []string{"b", "a"}, wantScore: "Sets 1:1, Legs 1:0"},
+		{winner: "a", wantPlayers: []string{"a", "b"}, wantScore: "Sets 2:1, Legs 0:0", finished: true},
+	}
+
+	for _, leg := range legs {
+		assert.Equal(t, leg.wantPlayers, m.StartingOrder())
+
+		finished, err := m.FinishLeg(leg.winner)
+		require.NoError(t, err)
+		assert.Equal(t, leg.finished, finished)
+		assert.Equal(t, leg.wantScore, m.Score())
+	}
+
+	assert.Equal(t, "a", m.GetWinner())
+	assert.Len(t, m.Results(), 7)
+	assert.Equal(t, LegResult{Set: 3, Leg: 2, Winner: "a"}, m.Results()[6])
+}
+
+func TestMatch_UnknownPlayer(t *testing.T) {
+	m := New([]string{"a", "b"}, 1, 3)
+
+	_, err := m.FinishLeg("c")
+	require.Error(t, err)
+	assert.Equal(t, "Legs 0:0", m.Score())
+}
@@ -0,0 +1,160 @@
+package multiplayer
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/views/common"
+	"github.com/Gerrit91/darts-counter/pkg/views/confirm-dialog"
+	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type (
+	// client is the terminal ui of a single connection, it shows the shared game and passes the input
+	// of the current player to it
+	client struct {
+		log     *slog.Logger
+		session *Session
+
+		// player is the profile of the connected user, nil for spectators
+		player *datastore.PlayerProfile
+		names  datastore.PlayerNames
+
+		currentView common.View
+		gameDetails *gamedetails.Model
+		undoDialog  tea.Model
+		err         error
+	}
+)
+
+// newClient creates the terminal ui of a connection, an empty player ID connects a spectator
+func newClient(log *slog.Logger, s *Session, playerID string) (*client, error) {
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &client{
+		log:         log,
+		session:     s,
+		names:       datastore.ToPlayerNames(profiles),
+		currentView: common.GameView,
+		gameDetails: gamedetails.New(log, s.ds),
+		undoDialog: confirm.New(
+			log,
+			"Are you sure you want to undo the last move?",
+			tea.Sequence(common.SwitchViewTo(common.GameView), common.UndoMove),
+			common.SwitchViewTo(common.GameView),
+		),
+	}
+
+	c.gameDetails.SetBackTo(common.SwitchViewTo(common.GameView))
+
+	for _, p := range profiles {
+		if playerID != "" && p.ID == playerID {
+			c.player = p
+		}
+	}
+
+	return c, nil
+}
+
+func (c *client) Init() tea.Cmd {
+	return nil
+}
+
+func (c *client) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case refreshMsg:
+		return c, nil
+	case tea.WindowSizeMsg:
+		_, cmd := c.gameDetails.Update(msg)
+		return c, cmd
+	case common.SwitchViewMsg:
+		c.currentView = msg.To()
+		return c, nil
+	case common.UndoMoveMsg:
+		// the turn could have changed while the dialog was shown
+		if c.err = c.session.checkTurn(c.player, c.names); c.err != nil {
+			return c, nil
+		}
+
+		c.session.update(msg)
+		return c, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return c, tea.Quit
+		}
+
+		switch c.currentView {
+		case common.GameDetailsView:
+			_, cmd := c.gameDetails.Update(msg)
+			return c, cmd
+		case common.UndoMoveView:
+			_, cmd := c.undoDialog.Update(msg)
+			return c, cmd
+		}
+
+		c.err = nil
+
+		switch msg.String() {
+		case "q", "esc":
+			// only the connection is closed, the game goes on
+			return c, tea.Quit
+		case "v":
+			c.gameDetails.SetGameStats(*c.session.gameStats())
+			c.currentView = common.GameDetailsView
+			return c, c.gameDetails.Init()
+		}
+
+		if c.err = c.session.checkTurn(c.player, c.names); c.err != nil {
+			return c, nil
+		}
+
+		if msg.String() == "u" {
+			c.currentView = common.UndoMoveView
+			return c, c.undoDialog.Init()
+		}
+
+		c.session.update(msg)
+	}
+
+	return c, nil
+}
+
+func (c *client) View() string {
+	switch c.currentView {
+	case common.GameDetailsView:
+		return c.gameDetails.View()
+	case common.UndoMoveView:
+		return c.undoDialog.View()
+	}
+
+	status := "Watching the game"
+	if c.player != nil {
+		status = "Playing as " + c.player.Name
+	}
+
+	terminals := "terminals"
+	n := c.session.connected()
+	if n == 1 {
+		terminals = "terminal"
+	}
+	status += fmt.Sprintf(", %d %s connected (q leaves the game without ending it)", n, terminals)
+
+	lines := []string{
+		c.session.view(),
+		"",
+		common.StyleInactive.Render(status),
+	}
+
+	if c.err != nil {
+		lines = append(lines, common.StyleError.Render(c.err.Error()))
+	}
+
+	return strings.Join(lines, "\n")
+}
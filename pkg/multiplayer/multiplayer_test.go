@@ -0,0 +1,266 @@
+package multiplayer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+type (
+	// terminal is an ssh connection to the server with a pty
+	terminal struct {
+		in io.WriteCloser
+
+		mu  sync.Mutex
+		out bytes.Buffer
+	}
+)
+
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.out.Write(p)
+}
+
+func (t *terminal) output() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.out.String()
+}
+
+func newTestServer(t *testing.T, c *config.SSHConfig) (*Session, *Server) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ds, err := datastore.New(log, &config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(ds.Close)
+
+	require.NoError(t, ds.UpdateGameSettings(&datastore.GameSettings{
+		Type:     config.GameType301,
+		Checkout: checkout.CheckoutTypeDoubleOut,
+		Checkin:  checkout.CheckinTypeStraightIn,
+		Players:  []datastore.Player{{Name: "Alice"}, {Name: "Bob"}},
+
+		SaveGameToStats: true,
+	}))
+
+	session, err := NewSession(log, ds, scoreboard.NewBroadcaster(log))
+	require.NoError(t, err)
+
+	if c.HostKeyPath == "" {
+		c.HostKeyPath = filepath.Join(t.TempDir(), "host_key")
+	}
+
+	srv, err := NewServer(log, session, c)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start("127.0.0.1:0"))
+	t.Cleanup(srv.Close)
+
+	return session, srv
+}
+
+func dial(srv *Server, user string, auth ...ssh.AuthMethod) (*ssh.Client, error) {
+	return ssh.Dial("tcp", srv.Addr().String(), &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	return signer
+}
+
+// writeAuthorizedKeys writes an authorized keys file, the keys are given with their comments
+func writeAuthorizedKeys(t *testing.T, keys map[string]ssh.Signer) string {
+	var buf bytes.Buffer
+	for comment, signer := range keys {
+		buf.Write(bytes.TrimSpace(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+		buf.WriteString(" " + comment + "\n")
+	}
+
+	path := filepath.Join(t.TempDir(), "authorized_keys")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+
+	return path
+}
+
+func connect(t *testing.T, srv *Server, user string, auth ...ssh.AuthMethod) *terminal {
+	conn, err := dial(srv, user, auth...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	sess, err := conn.NewSession()
+	require.NoError(t, err)
+
+	term := &terminal{}
+	sess.Stdout = term
+
+	term.in, err = sess.StdinPipe()
+	require.NoError(t, err)
+
+	require.NoError(t, sess.RequestPty("xterm", 40, 120, ssh.TerminalModes{}))
+	require.NoError(t, sess.Shell())
+
+	require.Eventually(t, func() bool {
+		return bytes.Contains([]byte(term.output()), []byte("q leaves the game"))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return term
+}
+
+func (t *terminal) send(tt *testing.T, input string) {
+	_, err := t.in.Write([]byte(input))
+	require.NoError(tt, err)
+}
+
+func (t *terminal) waitFor(tt *testing.T, text string) {
+	require.Eventually(tt, func() bool {
+		return bytes.Contains([]byte(t.output()), []byte(text))
+	}, 5*time.Second, 10*time.Millisecond, "terminal did not show %q", text)
+}
+
+func TestServer_SharedGame(t *testing.T) {
+	var (
+		aliceKey = newSigner(t)
+		bobKey   = newSigner(t)
+		carolKey = newSigner(t)
+	)
+
+	session, srv := newTestServer(t, &config.SSHConfig{
+		AuthorizedKeysPath: writeAuthorizedKeys(t, map[string]ssh.Signer{
+			"Alice": aliceKey,
+			"bob":   bobKey,
+			// carol is not a player of the game
+			"carol": carolKey,
+		}),
+	})
+
+	// the player is determined by the key, not by the user name
+	alice := connect(t, srv, "bob", ssh.PublicKeys(aliceKey))
+	bob := connect(t, srv, "bob", ssh.PublicKeys(bobKey))
+	carol := connect(t, srv, "alice", ssh.PublicKeys(carolKey))
+
+	alice.waitFor(t, "Playing as Alice")
+	bob.waitFor(t, "Playing as Bob")
+	carol.waitFor(t, "Watching the game")
+
+	require.Eventually(t, func() bool { return session.connected() == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	// only the current player can enter scores
+	bob.send(t, "60\r")
+	bob.waitFor(t, "it is Alice's turn")
+
+	carol.send(t, "60\r")
+	carol.waitFor(t, "you are watching the game")
+
+	assert.Empty(t, session.gameStats().Moves)
+
+	alice.send(t, "T20 T20 T20\r")
+
+	require.Eventually(t, func() bool { return len(session.gameStats().Moves) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 121, session.gameStats().Moves[0].Remaining)
+
+	// all terminals show the new state
+	for _, term := range []*terminal{alice, bob, carol} {
+		term.waitFor(t, "121")
+	}
+
+	bob.send(t, "41\r")
+	require.Eventually(t, func() bool { return len(session.gameStats().Moves) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 260, session.gameStats().Moves[1].Remaining)
+
+	alice.send(t, "T20 T11 D14\r")
+	alice.waitFor(t, "Game finished.")
+
+	// after the game is finished, every player can continue with the next game
+	carol.send(t, "\r")
+	carol.waitFor(t, "you are watching the game")
+
+	id := session.gameStats().ID
+
+	bob.send(t, "\r")
+	require.Eventually(t, func() bool { return session.gameStats().ID != id }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, session.gameStats().Moves)
+
+	games, err := session.ds.ListGameStats()
+	require.NoError(t, err)
+	require.Len(t, games, 1)
+	assert.Equal(t, id, games[0].ID)
+
+	// leaving does not end the game
+	alice.send(t, "T20\r")
+	require.Eventually(t, func() bool { return len(session.gameStats().Moves) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	alice.send(t, "q")
+	require.Eventually(t, func() bool { return session.connected() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, session.gameStats().Moves, 1)
+}
+
+func TestServer_WithoutAuthorizedKeys(t *testing.T) {
+	session, srv := newTestServer(t, &config.SSHConfig{})
+
+	// without keys, the user name does not make a player
+	alice := connect(t, srv, "alice")
+	alice.waitFor(t, "Watching the game")
+
+	alice.send(t, "60\r")
+	alice.waitFor(t, "you are watching the game")
+	assert.Empty(t, session.gameStats().Moves)
+}
+
+func TestServer_AuthorizedKeys(t *testing.T) {
+	authorized, unknown := newSigner(t), newSigner(t)
+
+	_, srv := newTestServer(t, &config.SSHConfig{
+		AuthorizedKeysPath: writeAuthorizedKeys(t, map[string]ssh.Signer{"Alice": authorized}),
+	})
+
+	conn, err := dial(srv, "alice", ssh.PublicKeys(authorized))
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	_, err = dial(srv, "alice", ssh.PublicKeys(unknown))
+	require.Error(t, err)
+
+	_, err = dial(srv, "alice")
+	require.Error(t, err)
+}
+
+func TestHostKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "host_key")
+
+	generated, err := hostKey(path)
+	require.NoError(t, err)
+
+	// the generated key is used again after a restart
+	read, err := hostKey(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, generated.PublicKey().Marshal(), read.PublicKey().Marshal())
+}
@@ -0,0 +1,259 @@
+package multiplayer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Gerrit91/darts-counter/pkg/config"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+	"github.com/Gerrit91/darts-counter/pkg/views/common"
+	cricketgame "github.com/Gerrit91/darts-counter/pkg/views/cricket-game"
+	"github.com/Gerrit91/darts-counter/pkg/views/game"
+	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type (
+	// Game is the model of a running game as used by the terminal ui
+	Game interface {
+		tea.Model
+		CurrentPlayerID() string
+		GameStats() *datastore.GameStats
+	}
+
+	// Session is a game that is shared by all connected terminals. The game is only accessed with the lock held,
+	// its commands are run in the background and after every change all terminals are refreshed.
+	Session struct {
+		log   *slog.Logger
+		ds    datastore.Datastore
+		board *scoreboard.Broadcaster
+
+		mu      sync.Mutex
+		game    Game
+		err     error
+		clients map[*tea.Program]struct{}
+	}
+
+	// refreshMsg tells a terminal to render the shared game again
+	refreshMsg struct{}
+)
+
+// NewSession resumes the game of the last checkpoint or starts a new game with the stored game settings
+func NewSession(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster) (*Session, error) {
+	s := &Session{
+		log:     log,
+		ds:      ds,
+		board:   board,
+		clients: map[*tea.Program]struct{}{},
+	}
+
+	cp, err := ds.GetCheckpoint()
+	switch {
+	case err == nil:
+		err = s.start(func(show *gamedetails.Model) (Game, error) {
+			if cp.Settings.Type == config.GameTypeCricket {
+				return cricketgame.Resume(log, ds, board, show)
+			}
+			return game.Resume(log, ds, board, show)
+		})
+	case errors.Is(err, datastore.ErrNotFound):
+		err = s.start(s.newGame)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// newGame starts a game with the stored game settings like the main menu does
+func (s *Session) newGame(show *gamedetails.Model) (Game, error) {
+	settings, err := s.ds.GetGameSettings()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve game settings, please configure them in the terminal ui first: %w", err)
+	}
+
+	if settings.Type == config.GameTypeCricket {
+		return cricketgame.New(s.log, s.ds, s.board, show)
+	}
+
+	return game.New(s.log, s.ds, s.board, show)
+}
+
+// start replaces the shared game with the one returned by the given function
+func (s *Session) start(create func(show *gamedetails.Model) (Game, error)) error {
+	g, err := create(gamedetails.New(s.log, s.ds))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.game = g
+	s.err = nil
+	cmd := g.Init()
+	s.log.Info("started shared game", "id", g.GameStats().ID)
+	s.mu.Unlock()
+
+	s.run(cmd)
+	s.refresh()
+
+	return nil
+}
+
+// update passes a message to the shared game
+func (s *Session) update(msg tea.Msg) {
+	s.mu.Lock()
+	_, cmd := s.game.Update(msg)
+	s.mu.Unlock()
+
+	s.run(cmd)
+	s.refresh()
+}
+
+// run executes a command of the shared game in the background, the resulting message is passed back to the game
+func (s *Session) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+
+	go func() {
+		s.handle(cmd())
+	}()
+}
+
+func (s *Session) handle(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			s.run(cmd)
+		}
+	case common.SwitchViewMsg:
+		// the views of the terminals are switched by the clients themselves, the game only switches
+		// back to the main menu after it is over
+		if msg.To() != common.MainMenuView {
+			s.log.Info("ignoring view switch of shared game", "to", msg.To())
+			return
+		}
+
+		err := s.start(s.newGame)
+		if err != nil {
+			s.log.Error("unable to start next shared game", "error", err)
+
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+
+			s.refresh()
+		}
+	default:
+		s.update(msg)
+	}
+}
+
+// view renders the shared game
+func (s *Session) view() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return common.StyleError.Render(s.err.Error())
+	}
+
+	return s.game.View()
+}
+
+// gameStats returns the statistics of the shared game, the moves are copied because the game changes them
+// while the statistics are shown
+func (s *Session) gameStats() *datastore.GameStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gs := s.game.GameStats()
+	gs.Moves = slices.Clone(gs.Moves)
+
+	return gs
+}
+
+// checkTurn returns an error if the given player may not control the shared game. Only the current player
+// can enter scores, after a leg is finished every player of the game can continue.
+func (s *Session) checkTurn(p *datastore.PlayerProfile, names datastore.PlayerNames) error {
+	if p == nil {
+		return fmt.Errorf("you are watching the game, only the keys of players can enter scores")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	current := s.game.CurrentPlayerID()
+
+	switch {
+	case current == p.ID:
+		return nil
+	case current != "":
+		return fmt.Errorf("it is %s's turn", names.Of(current))
+	case slices.Contains(s.game.GameStats().Players, p.ID):
+		return nil
+	default:
+		return fmt.Errorf("%s does not play in this game", p.Name)
+	}
+}
+
+// playerID returns the ID of the player with the given name or ID, it is empty if there is no such player
+func (s *Session) playerID(nameOrID string) (string, error) {
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range profiles {
+		if p.ID == nameOrID || strings.EqualFold(p.Name, nameOrID) {
+			return p.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (s *Session) join(p *tea.Program) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients[p] = struct{}{}
+}
+
+func (s *Session) leave(p *tea.Program) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.clients, p)
+}
+
+// connected returns the number of connected terminals
+func (s *Session) connected() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients)
+}
+
+// refresh lets all terminals render the shared game again. The messages are sent in the background because
+// refresh is also called from the update loops of the terminals.
+func (s *Session) refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for p := range s.clients {
+		go p.Send(refreshMsg{})
+	}
+}
@@ -0,0 +1,329 @@
+package multiplayer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+
+	"github.com/Gerrit91/darts-counter/pkg/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/crypto/ssh"
+)
+
+type (
+	// Server accepts ssh connections and shows the shared game in every connected terminal. The keys of the
+	// authorized keys file are assigned to players by their comment, all other users watch the game.
+	Server struct {
+		log     *slog.Logger
+		session *Session
+		config  *ssh.ServerConfig
+		ln      net.Listener
+	}
+
+	ptyRequest struct {
+		Term    string
+		Columns uint32
+		Rows    uint32
+		Width   uint32
+		Height  uint32
+		Modes   string
+	}
+
+	windowChangeRequest struct {
+		Columns uint32
+		Rows    uint32
+		Width   uint32
+		Height  uint32
+	}
+
+	exitStatus struct {
+		Status uint32
+	}
+
+	// authorizedKey is a key of the authorized keys file, the comment contains the name or ID of the player
+	authorizedKey struct {
+		key     ssh.PublicKey
+		comment string
+	}
+)
+
+// playerExtension is the permission extension that contains the ID of the player an ssh key belongs to
+const playerExtension = "darts-counter-player"
+
+func NewServer(log *slog.Logger, s *Session, c *config.SSHConfig) (*Server, error) {
+	signer, err := hostKey(c.HostKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &ssh.ServerConfig{}
+	sc.AddHostKey(signer)
+
+	if c.AuthorizedKeysPath == "" {
+		// without keys, nobody can prove to be a player, so everybody watches
+		sc.NoClientAuth = true
+	} else {
+		authorized, err := authorizedKeys(c.AuthorizedKeysPath)
+		if err != nil {
+			return nil, err
+		}
+
+		sc.PublicKeyCallback = func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			for _, k := range authorized {
+				if !bytes.Equal(k.key.Marshal(), key.Marshal()) {
+					continue
+				}
+
+				id, err := s.playerID(k.comment)
+				if err != nil {
+					return nil, err
+				}
+
+				return &ssh.Permissions{Extensions: map[string]string{playerExtension: id}}, nil
+			}
+
+			return nil, fmt.Errorf("public key of %q is not authorized", meta.User())
+		}
+	}
+
+	// the color support of the terminals cannot be detected, most terminals support 256 colors
+	lipgloss.SetColorProfile(termenv.ANSI256)
+
+	return &Server{
+		log:     log,
+		session: s,
+		config:  sc,
+	}, nil
+}
+
+// Start listens on the given address and accepts connections in the background, it returns an error
+// right away if the address cannot be used
+func (srv *Server) Start(address string) error {
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", address, err)
+	}
+
+	srv.ln = ln
+
+	srv.log.Info("serving ssh", "address", ln.Addr().String())
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					srv.log.Error("ssh server stopped", "error", err)
+				}
+				return
+			}
+
+			go srv.handleConn(conn)
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the server listens on
+func (srv *Server) Addr() net.Addr {
+	return srv.ln.Addr()
+}
+
+func (srv *Server) Close() {
+	if err := srv.ln.Close(); err != nil {
+		srv.log.Error("error closing ssh server", "error", err)
+	}
+}
+
+func (srv *Server) handleConn(nc net.Conn) {
+	conn, chans, reqs, err := ssh.NewServerConn(nc, srv.config)
+	if err != nil {
+		srv.log.Info("ssh handshake failed", "remote", nc.RemoteAddr().String(), "error", err)
+		_ = nc.Close()
+		return
+	}
+	defer conn.Close()
+
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+
+		ch, requests, err := newChannel.Accept()
+		if err != nil {
+			srv.log.Error("unable to accept ssh session", "error", err)
+			continue
+		}
+
+		go srv.handleSession(conn, ch, requests)
+	}
+}
+
+// handleSession runs the terminal ui as soon as the client requests a shell, the ui requires a terminal
+func (srv *Server) handleSession(conn *ssh.ServerConn, ch ssh.Channel, requests <-chan *ssh.Request) {
+	defer ch.Close()
+
+	var (
+		pty  *ptyRequest
+		p    *tea.Program
+		size tea.WindowSizeMsg
+	)
+
+	for req := range requests {
+		ok := false
+
+		switch req.Type {
+		case "pty-req":
+			pty = &ptyRequest{}
+			if err := ssh.Unmarshal(req.Payload, pty); err == nil {
+				size = tea.WindowSizeMsg{Width: int(pty.Columns), Height: int(pty.Rows)}
+				ok = true
+			}
+		case "window-change":
+			var wc windowChangeRequest
+			if err := ssh.Unmarshal(req.Payload, &wc); err == nil {
+				size = tea.WindowSizeMsg{Width: int(wc.Columns), Height: int(wc.Rows)}
+				if p != nil {
+					go p.Send(size)
+				}
+				ok = true
+			}
+		case "shell":
+			if p != nil {
+				break
+			}
+
+			if pty == nil {
+				_, _ = fmt.Fprint(ch, "a terminal is required, please connect with ssh -t\r\n")
+				_ = req.Reply(false, nil)
+				return
+			}
+
+			var playerID string
+			if conn.Permissions != nil {
+				playerID = conn.Permissions.Extensions[playerExtension]
+			}
+
+			client, err := newClient(srv.log, srv.session, playerID)
+			if err != nil {
+				srv.log.Error("unable to create terminal ui for ssh connection", "user", conn.User(), "error", err)
+				_, _ = fmt.Fprintf(ch, "unable to join the game: %s\r\n", err)
+				_ = req.Reply(false, nil)
+				return
+			}
+
+			p = tea.NewProgram(client,
+				tea.WithInput(ch),
+				tea.WithOutput(ch),
+				tea.WithEnvironment([]string{"TERM=" + pty.Term}),
+				tea.WithAltScreen(),
+				tea.WithoutSignalHandler(),
+			)
+
+			go srv.run(conn, ch, p, client, size)
+
+			ok = true
+		}
+
+		if req.WantReply {
+			_ = req.Reply(ok, nil)
+		}
+	}
+
+	// the client closed the session
+	if p != nil {
+		p.Quit()
+	}
+}
+
+// run runs the terminal ui of a connection until the user leaves the game or the connection is closed
+func (srv *Server) run(conn *ssh.ServerConn, ch ssh.Channel, p *tea.Program, c *client, size tea.WindowSizeMsg) {
+	log := srv.log.With("user", conn.User(), "remote", conn.RemoteAddr().String())
+
+	log.Info("terminal connected", "spectator", c.player == nil)
+
+	srv.session.join(p)
+	srv.session.refresh()
+
+	go p.Send(size)
+
+	_, err := p.Run()
+	if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		log.Error("terminal ui stopped", "error", err)
+	}
+
+	srv.session.leave(p)
+	srv.session.refresh()
+
+	_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(exitStatus{}))
+	_ = ch.Close()
+
+	log.Info("terminal disconnected")
+}
+
+// hostKey reads the private key of the server, a new key is generated if the file does not exist yet
+func hostKey(path string) (ssh.Signer, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate host key: %w", err)
+		}
+
+		block, err := ssh.MarshalPrivateKey(key, "darts-counter")
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode host key: %w", err)
+		}
+
+		raw = pem.EncodeToMemory(block)
+
+		err = os.WriteFile(path, raw, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("unable to write host key: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read host key: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse host key: %w", err)
+	}
+
+	return signer, nil
+}
+
+func authorizedKeys(path string) ([]authorizedKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read authorized keys: %w", err)
+	}
+
+	var keys []authorizedKey
+
+	for len(bytes.TrimSpace(raw)) > 0 {
+		key, comment, _, rest, err := ssh.ParseAuthorizedKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse authorized keys: %w", err)
+		}
+
+		keys = append(keys, authorizedKey{key: key, comment: comment})
+		raw = rest
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,86 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+)
+
+// AroundTheClockTargets returns the targets that have to be hit in order, 1 through 20 and the bullseye
+func AroundTheClockTargets() []int {
+	var targets []int
+
+	for i := range 20 {
+		targets = append(targets, i+1)
+	}
+
+	return append(targets, checkout.BullsEye)
+}
+
+// NewAroundTheClock creates a player for an around-the-clock game, the remaining score counts
+// the targets that are left to hit. If skipAhead is set, doubles and triples advance the player
+// by two or three targets.
+func NewAroundTheClock(name string, skipAhead bool) *Player {
+	targets := len(AroundTheClockTargets())
+
+	return &Player{
+		name:       name,
+		remaining:  targets,
+		startScore: targets,
+		skipAhead:  skipAhead,
+	}
+}
+
+// GetTarget returns the target the player has to hit next, zero if all targets were hit
+func (p *Player) GetTarget() int {
+	targets := AroundTheClockTargets()
+
+	if p.remaining <= 0 || p.remaining > len(targets) {
+		return 0
+	}
+
+	return targets[len(targets)-p.remaining]
+}
+
+// MoveAroundTheClock applies the thrown darts and returns the amount of targets the player advanced
+// and the amount of darts that were thrown in this turn
+func (p *Player) MoveAroundTheClock(scores []*checkout.Score) (int, int, error) {
+	if len(scores) > 3 {
+		return 0, 0, fmt.Errorf("%w: no more than three throws are allowed", ErrInvalidInput)
+	}
+
+	var (
+		advanced int
+		darts    = 3
+	)
+
+	for i, score := range scores {
+		target := p.GetTarget()
+
+		if score.IsMiss() || score.Value()/score.GetMultiplier().Value() != target {
+			continue
+		}
+
+		step := 1
+		if p.skipAhead {
+			step = score.GetMultiplier().Value()
+		}
+
+		// skipping ahead never skips the bullseye, it always needs to be hit
+		step = min(step, p.remaining-1)
+		if target == checkout.BullsEye {
+			step = 1
+		}
+
+		p.remaining -= step
+		advanced += step
+
+		if p.remaining == 0 {
+			p.finished = true
+			darts = i + 1
+			break
+		}
+	}
+
+	return advanced, darts, nil
+}
@@ -0,0 +1,66 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseScores(t *testing.T, fields ...string) []*checkout.Score {
+	var scores []*checkout.Score
+
+	for _, f := range fields {
+		s, err := checkout.ParseScore(f)
+		require.NoError(t, err)
+		scores = append(scores, s)
+	}
+
+	return scores
+}
+
+func TestPlayer_MoveAroundTheClock(t *testing.T) {
+	p := NewAroundTheClock("1", false)
+	assert.Equal(t, 1, p.GetTarget())
+
+	advanced, darts, err := p.MoveAroundTheClock(parseScores(t, "1", "M", "T2"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, advanced)
+	assert.Equal(t, 3, darts)
+	assert.Equal(t, 3, p.GetTarget())
+
+	advanced, darts, err = p.MoveAroundTheClock(parseScores(t, "5", "4"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, advanced)
+	assert.Equal(t, 3, darts)
+	assert.Equal(t, 3, p.GetTarget())
+
+	require.NoError(t, p.Edit(2))
+	assert.Equal(t, 20, p.GetTarget())
+
+	advanced, darts, err = p.MoveAroundTheClock(parseScores(t, "20", "B", "20"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, advanced)
+	assert.Equal(t, 2, darts)
+	assert.True(t, p.HasFinished())
+	assert.Equal(t, 0, p.GetTarget())
+}
+
+func TestPlayer_MoveAroundTheClockSkipAhead(t *testing.T) {
+	p := NewAroundTheClock("1", true)
+
+	advanced, _, err := p.MoveAroundTheClock(parseScores(t, "T1", "D4", "5"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, advanced)
+	assert.Equal(t, 6, p.GetTarget())
+
+	require.NoError(t, p.Edit(2))
+
+	// a triple on the last number does not skip the bullseye
+	advanced, darts, err := p.MoveAroundTheClock(parseScores(t, "T20", "DB"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, advanced)
+	assert.Equal(t, 2, darts)
+	assert.True(t, p.HasFinished())
+}
@@ -51,13 +51,13 @@ func (i *Iterator) Next() (*Player, error) {
 	return nil, ErrGameFinished
 }
 
-func (i *Iterator) SetBackTo(name string) (*Player, error) {
+func (i *Iterator) SetBackTo(id string) (*Player, error) {
 	playerIdx := slices.IndexFunc(i.players, func(p *Player) bool {
-		return p.name == name
+		return p.GetID() == id
 	})
 
 	if playerIdx < 0 {
-		return nil, fmt.Errorf("no player found with name %q", name)
+		return nil, fmt.Errorf("no player found with id %q", id)
 	}
 
 	if playerIdx >= i.nextIdx {
@@ -10,10 +10,12 @@ import (
 var (
 	ErrGameFinished = fmt.Errorf("no more players left in the game")
 	ErrInvalidInput = fmt.Errorf("invalid input")
+	ErrBust         = fmt.Errorf("bust")
 )
 
 type (
 	Player struct {
+		id         string
 		name       string
 		out        checkout.CheckoutType
 		in         checkout.CheckinType
@@ -21,6 +23,8 @@ type (
 		startScore int
 		rank       int
 		finished   bool
+
+		skipAhead bool
 	}
 
 	Players []*Player
@@ -36,6 +40,16 @@ func (ps Players) Names() []string {
 	return names
 }
 
+func (ps Players) IDs() []string {
+	var ids []string
+
+	for _, p := range ps {
+		ids = append(ids, p.GetID())
+	}
+
+	return ids
+}
+
 func New(name string, out checkout.CheckoutType, in checkout.CheckinType, remaining int) *Player {
 	return &Player{
 		name:       name,
@@ -46,10 +60,14 @@ func New(name string, out checkout.CheckoutType, in checkout.CheckinType, remain
 	}
 }
 
-func (p *Player) Move(scores []*checkout.Score, total int) error {
+// Move applies a player's turn and returns the points that counted for the remaining score.
+// On a bust, ErrBust is returned and the remaining score stays at the start of the turn.
+func (p *Player) Move(scores []*checkout.Score, total int) (int, error) {
+	scores, total = p.checkIn(scores, total)
+
 	err := p.validateInput(scores, total)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	p.remaining = p.remaining - total
@@ -58,7 +76,27 @@ func (p *Player) Move(scores []*checkout.Score, total int) error {
 		p.finished = true
 	}
 
-	return nil
+	return total, nil
+}
+
+// Counted returns the points of the given darts that count for the remaining score
+func (p *Player) Counted(scores []*checkout.Score) int {
+	_, total := p.checkIn(scores, sum(scores))
+	return total
+}
+
+// checkIn drops the darts that were thrown before the player checked in, e.g. with a double in double-in games
+func (p *Player) checkIn(scores []*checkout.Score, total int) ([]*checkout.Score, int) {
+	if p.in == checkout.CheckinTypeStraightIn || p.remaining != p.startScore || len(scores) == 0 {
+		return scores, total
+	}
+
+	idx := slices.IndexFunc(scores, p.in.IsCheckin)
+	if idx < 0 {
+		return nil, 0
+	}
+
+	return scores[idx:], sum(scores[idx:])
 }
 
 func (p *Player) Edit(total int) error {
@@ -80,12 +118,6 @@ func (p *Player) Edit(total int) error {
 }
 
 func (p *Player) validateInput(scores []*checkout.Score, total int) error {
-	if p.in == checkout.CheckinTypeDoubleIn && p.remaining == p.startScore {
-		if len(scores) != 0 && scores[0].GetMultiplier() != checkout.Double {
-			return fmt.Errorf("selected game requires double-in, but did not start with double")
-		}
-	}
-
 	if total < 0 {
 		return fmt.Errorf("%w: score must be a positive number", ErrInvalidInput)
 	}
@@ -98,20 +130,9 @@ func (p *Player) validateInput(scores []*checkout.Score, total int) error {
 		return fmt.Errorf("%w: not possible to achieve %d points in one turn (bogey number)", ErrInvalidInput, total)
 	}
 
-	newScore := p.remaining - total
-
-	if newScore < 0 {
-		return fmt.Errorf("%s exceeded the remaining score of %d", p.name, p.remaining)
-	}
-
-	if p.out == checkout.CheckoutTypeDoubleOut && newScore == 1 {
-		return fmt.Errorf("in double-out games, remaining 1 is considered overshoot")
-	}
-
-	if p.out == checkout.CheckoutTypeDoubleOut && newScore == 0 {
-		if len(scores) != 0 && scores[len(scores)-1].GetMultiplier() != checkout.Double {
-			return fmt.Errorf("selected game requires double-out, but did not checkout with double")
-		}
+	err := p.checkBust(scores, p.remaining-total)
+	if err != nil {
+		return err
 	}
 
 	if p.remaining > 180 {
@@ -126,6 +147,51 @@ func (p *Player) validateInput(scores []*checkout.Score, total int) error {
 	return nil
 }
 
+// CheckBust returns an error if the darts thrown so far in the current turn already bust the player,
+// which allows detecting a bust before the turn is complete
+func (p *Player) CheckBust(scores []*checkout.Score) error {
+	scores, total := p.checkIn(scores, sum(scores))
+
+	return p.checkBust(scores, p.remaining-total)
+}
+
+func (p *Player) checkBust(scores []*checkout.Score, newScore int) error {
+	if newScore < 0 {
+		return fmt.Errorf("%w: %s exceeded the remaining score of %d", ErrBust, p.name, p.remaining)
+	}
+
+	if p.out.LeavesOneBust() && newScore == 1 {
+		return fmt.Errorf("%w: in %s games, remaining 1 is considered overshoot", ErrBust, p.out)
+	}
+
+	if newScore == 0 && len(scores) != 0 && !p.out.IsFinish(scores[len(scores)-1]) {
+		switch p.out {
+		case checkout.CheckoutTypeMasterOut:
+			return fmt.Errorf("%w: selected game requires master-out, but did not checkout with double or triple", ErrBust)
+		default:
+			return fmt.Errorf("%w: selected game requires double-out, but did not checkout with double", ErrBust)
+		}
+	}
+
+	return nil
+}
+
+// WithID sets the ID of the player profile, which identifies the player in moves and ranks
+func (p *Player) WithID(id string) *Player {
+	p.id = id
+
+	return p
+}
+
+// GetID returns the ID of the player profile, falling back to the name if no ID was set
+func (p *Player) GetID() string {
+	if p.id == "" {
+		return p.name
+	}
+
+	return p.id
+}
+
 func (p *Player) GetName() string {
 	return p.name
 }
@@ -145,3 +211,11 @@ func (p *Player) HasFinished() bool {
 func (p *Player) SetRank(rank int) {
 	p.rank = rank
 }
+
+func sum(scores []*checkout.Score) int {
+	total := 0
+	for _, s := range scores {
+		total += s.Value()
+	}
+	return total
+}
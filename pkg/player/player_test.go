@@ -0,0 +1,184 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayer_CheckBust(t *testing.T) {
+	tests := []struct {
+		name      string
+		out       checkout.CheckoutType
+		remaining int
+		fields    []string
+		wantErr   string
+	}{
+		{
+			name:      "no darts thrown",
+			out:       checkout.CheckoutTypeDoubleOut,
+			remaining: 40,
+		},
+		{
+			name:      "darts left in hand",
+			out:       checkout.CheckoutTypeDoubleOut,
+			remaining: 40,
+			fields:    []string{"20"},
+		},
+		{
+			name:      "exceeded on the first dart",
+			out:       checkout.CheckoutTypeStraightOut,
+			remaining: 40,
+			fields:    []string{"T20"},
+			wantErr:   "bust: 1 exceeded the remaining score of 40",
+		},
+		{
+			name:      "remaining one in double-out",
+			out:       checkout.CheckoutTypeDoubleOut,
+			remaining: 40,
+			fields:    []string{"19", "20"},
+			wantErr:   "bust: in double-out games, remaining 1 is considered overshoot",
+		},
+		{
+			name:      "remaining one in straight-out",
+			out:       checkout.CheckoutTypeStraightOut,
+			remaining: 40,
+			fields:    []string{"19", "20"},
+		},
+		{
+			name:      "finished without double",
+			out:       checkout.CheckoutTypeDoubleOut,
+			remaining: 40,
+			fields:    []string{"20", "20"},
+			wantErr:   "bust: selected game requires double-out, but did not checkout with double",
+		},
+		{
+			name:      "finished with double",
+			out:       checkout.CheckoutTypeDoubleOut,
+			remaining: 40,
+			fields:    []string{"M", "D20"},
+		},
+		{
+			name:      "finished with triple in master-out",
+			out:       checkout.CheckoutTypeMasterOut,
+			remaining: 60,
+			fields:    []string{"T20"},
+		},
+		{
+			name:      "finished with single in master-out",
+			out:       checkout.CheckoutTypeMasterOut,
+			remaining: 40,
+			fields:    []string{"20", "20"},
+			wantErr:   "bust: selected game requires master-out, but did not checkout with double or triple",
+		},
+		{
+			name:      "remaining one in master-out",
+			out:       checkout.CheckoutTypeMasterOut,
+			remaining: 40,
+			fields:    []string{"T13"},
+			wantErr:   "bust: in master-out games, remaining 1 is considered overshoot",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New("1", tt.out, checkout.CheckinTypeStraightIn, tt.remaining)
+
+			err := p.CheckBust(parseScores(t, tt.fields...))
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, ErrBust)
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.remaining, p.GetRemaining())
+		})
+	}
+}
+
+func TestPlayer_Move(t *testing.T) {
+	tests := []struct {
+		name          string
+		in            checkout.CheckinType
+		out           checkout.CheckoutType
+		remaining     int
+		fields        []string
+		wantCounted   int
+		wantRemaining int
+		wantErr       error
+	}{
+		{
+			name:          "regular turn",
+			in:            checkout.CheckinTypeStraightIn,
+			out:           checkout.CheckoutTypeDoubleOut,
+			remaining:     301,
+			fields:        []string{"T20", "20", "5"},
+			wantCounted:   85,
+			wantRemaining: 216,
+		},
+		{
+			name:          "bust keeps the remaining score",
+			in:            checkout.CheckinTypeStraightIn,
+			out:           checkout.CheckoutTypeDoubleOut,
+			remaining:     40,
+			fields:        []string{"20", "19"},
+			wantCounted:   0,
+			wantRemaining: 40,
+			wantErr:       ErrBust,
+		},
+		{
+			name:          "checkout",
+			in:            checkout.CheckinTypeStraightIn,
+			out:           checkout.CheckoutTypeDoubleOut,
+			remaining:     40,
+			fields:        []string{"D20"},
+			wantCounted:   40,
+			wantRemaining: 0,
+		},
+		{
+			name:          "double-in counts from the first double",
+			in:            checkout.CheckinTypeDoubleIn,
+			out:           checkout.CheckoutTypeDoubleOut,
+			remaining:     301,
+			fields:        []string{"T20", "D10", "20"},
+			wantCounted:   40,
+			wantRemaining: 261,
+		},
+		{
+			name:          "master-in counts from the first triple",
+			in:            checkout.CheckinTypeMasterIn,
+			out:           checkout.CheckoutTypeMasterOut,
+			remaining:     301,
+			fields:        []string{"20", "T20", "20"},
+			wantCounted:   80,
+			wantRemaining: 221,
+		},
+		{
+			name:          "double-in without double counts nothing",
+			in:            checkout.CheckinTypeDoubleIn,
+			out:           checkout.CheckoutTypeDoubleOut,
+			remaining:     301,
+			fields:        []string{"T20", "T20", "20"},
+			wantCounted:   0,
+			wantRemaining: 301,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New("1", tt.out, tt.in, tt.remaining)
+			scores := parseScores(t, tt.fields...)
+
+			counted, err := p.Move(scores, sum(scores))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantCounted, counted)
+			assert.Equal(t, tt.wantRemaining, p.GetRemaining())
+			assert.Equal(t, tt.wantRemaining == 0, p.HasFinished())
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package remote
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// replyTimeout is the time to wait for the terminal ui to process an input
+const replyTimeout = 5 * time.Second
+
+// the pairing code is typed on the remote device, so it avoids characters that are easily confused
+const (
+	pairingCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	pairingCodeLength   = 8
+)
+
+const (
+	ActionScore Action = "score"
+	ActionSkip  Action = "skip"
+	ActionUndo  Action = "undo"
+)
+
+//go:embed remote.html
+var page []byte
+
+type (
+	Action string
+
+	// InputMsg is an input entered on a remote device, the view that processes it must reply exactly once
+	InputMsg struct {
+		Action Action
+		// Input is the turn in the syntax of the score input of the terminal ui, only used by the score action
+		Input string
+
+		reply chan Result
+	}
+
+	// Result is returned to the remote device after an input was processed
+	Result struct {
+		Error string `json:"error,omitempty"`
+		// Message contains the message shown in the terminal ui after the input, e.g. the rank of a finished player
+		Message string `json:"message,omitempty"`
+		// Prompt tells which input is expected next
+		Prompt string `json:"prompt,omitempty"`
+	}
+
+	// Input passes inputs from remote devices to the terminal ui, which stays the source of truth of the game.
+	// Only devices that know the pairing code shown in the terminal ui can enter scores.
+	Input struct {
+		log     *slog.Logger
+		send    func(tea.Msg)
+		timeout time.Duration
+		code    string
+	}
+
+	inputRequest struct {
+		Code   string `json:"code"`
+		Action Action `json:"action"`
+		Input  string `json:"input"`
+	}
+)
+
+// NewInput creates a remote input that sends its messages with the given function, usually tea.Program.Send
+func NewInput(log *slog.Logger, send func(tea.Msg)) *Input {
+	return &Input{
+		log:     log,
+		send:    send,
+		timeout: replyTimeout,
+		code:    newPairingCode(),
+	}
+}
+
+func newPairingCode() string {
+	code := make([]byte, pairingCodeLength)
+	_, _ = rand.Read(code) // never returns an error
+
+	// the alphabet has 32 characters, so every character is equally likely
+	for i, b := range code {
+		code[i] = pairingCodeAlphabet[int(b)%len(pairingCodeAlphabet)]
+	}
+
+	return string(code)
+}
+
+// PairingCode returns the code that has to be entered on a remote device, it changes with every start
+func (i *Input) PairingCode() string {
+	return i.code
+}
+
+func NewInputMsg(action Action, input string) InputMsg {
+	return InputMsg{
+		Action: action,
+		Input:  input,
+		reply:  make(chan Result, 1),
+	}
+}
+
+// Reply returns the result of the input to the remote device, it never blocks
+func (m InputMsg) Reply(err error, message, prompt string) {
+	r := Result{
+		Message: message,
+		Prompt:  prompt,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	select {
+	case m.reply <- r:
+	default:
+	}
+}
+
+// Register adds the input form and its endpoint to the given mux
+func (i *Input) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /remote", i.servePage)
+	mux.HandleFunc("POST /remote/input", i.serveInput)
+}
+
+func (i *Input) servePage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if _, err := w.Write(page); err != nil {
+		i.log.Error("error writing remote input page", "error", err)
+	}
+}
+
+// serveInput passes an input to the terminal ui and waits until it was processed
+func (i *Input) serveInput(w http.ResponseWriter, r *http.Request) {
+	var (
+		req    inputRequest
+		status = http.StatusOK
+	)
+
+	result, err := func() (Result, error) {
+		// other content types could be sent by any website open in a browser of the network without a preflight request
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			status = http.StatusUnsupportedMediaType
+			return Result{}, errors.New("the input must be sent as application/json")
+		}
+
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&req)
+		if err != nil {
+			status = http.StatusBadRequest
+			return Result{}, fmt.Errorf("unable to decode input: %w", err)
+		}
+
+		if subtle.ConstantTimeCompare([]byte(req.Code), []byte(i.code)) != 1 {
+			status = http.StatusForbidden
+			return Result{}, errors.New("invalid pairing code, it is shown in the main menu of the terminal ui")
+		}
+
+		switch req.Action {
+		case ActionScore, ActionSkip, ActionUndo:
+		default:
+			status = http.StatusBadRequest
+			return Result{}, fmt.Errorf("unknown action: %q", req.Action)
+		}
+
+		msg := NewInputMsg(req.Action, req.Input)
+		i.send(msg)
+
+		select {
+		case result := <-msg.reply:
+			if result.Error != "" {
+				status = http.StatusUnprocessableEntity
+			}
+			return result, nil
+		case <-time.After(i.timeout):
+			status = http.StatusServiceUnavailable
+			return Result{}, errors.New("the terminal ui did not respond in time")
+		case <-r.Context().Done():
+			return Result{}, r.Context().Err()
+		}
+	}()
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	i.log.Info("received remote input", "action", req.Action, "input", req.Input, "remote", r.RemoteAddr, "error", result.Error)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		i.log.Error("error writing remote input result", "error", err)
+	}
+}
@@ -0,0 +1,163 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInput_ServeInput(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		send        func(tea.Msg)
+		wantStatus  int
+		want        Result
+	}{
+		{
+			name: "score is passed to the terminal ui",
+			body: `{"code":"ABCD2345","action":"score","input":"T20 T20 D10"}`,
+			send: func(msg tea.Msg) {
+				m := msg.(InputMsg)
+				if m.Action != ActionScore || m.Input != "T20 T20 D10" {
+					m.Reply(errors.New("unexpected input"), "", "")
+					return
+				}
+				m.Reply(nil, "Alice took 1. place!", "")
+			},
+			wantStatus: http.StatusOK,
+			want:       Result{Message: "Alice took 1. place!"},
+		},
+		{
+			name: "errors are returned",
+			body: `{"code":"ABCD2345","action":"score","input":"T25"}`,
+			send: func(msg tea.Msg) {
+				msg.(InputMsg).Reply(errors.New("invalid field"), "", "Enter score:")
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			want:       Result{Error: "invalid field", Prompt: "Enter score:"},
+		},
+		{
+			name: "input is replied only once",
+			body: `{"code":"ABCD2345","action":"undo"}`,
+			send: func(msg tea.Msg) {
+				msg.(InputMsg).Reply(nil, "", "Enter score:")
+				msg.(InputMsg).Reply(errors.New("second reply"), "", "")
+			},
+			wantStatus: http.StatusOK,
+			want:       Result{Prompt: "Enter score:"},
+		},
+		{
+			name:       "unknown action",
+			body:       `{"code":"ABCD2345","action":"restart"}`,
+			wantStatus: http.StatusBadRequest,
+			want:       Result{Error: `unknown action: "restart"`},
+		},
+		{
+			name:       "invalid pairing code",
+			body:       `{"code":"ABCD2346","action":"skip"}`,
+			wantStatus: http.StatusForbidden,
+			want:       Result{Error: "invalid pairing code, it is shown in the main menu of the terminal ui"},
+		},
+		{
+			name:       "missing pairing code",
+			body:       `{"action":"skip"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:        "content type without preflight request",
+			contentType: "text/plain",
+			body:        `{"code":"ABCD2345","action":"skip"}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			want:        Result{Error: "the input must be sent as application/json"},
+		},
+		{
+			name:       "invalid body",
+			body:       `skip`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "terminal ui does not respond",
+			body:       `{"code":"ABCD2345","action":"skip"}`,
+			send:       func(tea.Msg) {},
+			wantStatus: http.StatusServiceUnavailable,
+			want:       Result{Error: "the terminal ui did not respond in time"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewInput(slog.New(slog.NewTextHandler(io.Discard, nil)), func(msg tea.Msg) {
+				// like tea.Program.Send, the message is processed asynchronously
+				go tt.send(msg)
+			})
+			i.timeout = 100 * time.Millisecond
+			i.code = "ABCD2345"
+
+			mux := http.NewServeMux()
+			i.Register(mux)
+
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			contentType := "application/json"
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+
+			resp, err := http.Post(ts.URL+"/remote/input", contentType, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			var got Result
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.want == (Result{}) {
+				assert.NotEmpty(t, got.Error)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInput_Page(t *testing.T) {
+	mux := http.NewServeMux()
+	NewInput(slog.New(slog.NewTextHandler(io.Discard, nil)), nil).Register(mux)
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/remote")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "/remote/input")
+}
+
+func TestNewPairingCode(t *testing.T) {
+	code := newPairingCode()
+	assert.Len(t, code, pairingCodeLength)
+
+	for _, c := range code {
+		assert.Contains(t, pairingCodeAlphabet, string(c))
+	}
+
+	assert.NotEqual(t, code, newPairingCode())
+}
@@ -0,0 +1,188 @@
+package scoreboard
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// keepAlive is the interval in which comments are sent to idle viewers, so that proxies and browsers do not
+// close the connection
+const keepAlive = 15 * time.Second
+
+//go:embed scoreboard.html
+var page []byte
+
+type (
+	// State is the state of a running game as shown on the scoreboard
+	State struct {
+		Game       string         `json:"game"`
+		Set        int            `json:"set,omitempty"`
+		Leg        int            `json:"leg,omitempty"`
+		MatchScore string         `json:"match_score,omitempty"`
+		Round      int            `json:"round"`
+		Players    []PlayerState  `json:"players"`
+		LastMove   *LastMoveState `json:"last_move,omitempty"`
+		Message    string         `json:"message,omitempty"`
+		Finished   bool           `json:"finished"`
+		Updated    time.Time      `json:"updated"`
+	}
+
+	PlayerState struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		Remaining int    `json:"remaining"`
+		// Target is the next field to hit in around the clock games or the fields that are still open in cricket games
+		Target  string `json:"target,omitempty"`
+		Rank    int    `json:"rank,omitempty"`
+		Current bool   `json:"current"`
+		// Darts contains the darts of the current turn that were already entered in per-dart mode
+		Darts     []string `json:"darts,omitempty"`
+		Checkouts []string `json:"checkouts,omitempty"`
+	}
+
+	LastMoveState struct {
+		Player string   `json:"player"`
+		Score  int      `json:"score"`
+		Fields []string `json:"fields,omitempty"`
+		Bust   bool     `json:"bust,omitempty"`
+	}
+
+	// Broadcaster distributes the latest game state to the connected viewers. Publishing never blocks,
+	// slow viewers skip intermediate states and only receive the latest one.
+	Broadcaster struct {
+		log *slog.Logger
+
+		mu      sync.Mutex
+		latest  []byte
+		viewers map[chan struct{}]struct{}
+	}
+)
+
+func NewBroadcaster(log *slog.Logger) *Broadcaster {
+	return &Broadcaster{
+		log:     log,
+		viewers: map[chan struct{}]struct{}{},
+	}
+}
+
+// Publish replaces the latest state and notifies the viewers
+func (b *Broadcaster) Publish(s *State) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		b.log.Error("unable to encode scoreboard state", "error", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.latest = data
+
+	for notify := range b.viewers {
+		select {
+		case notify <- struct{}{}:
+		default:
+			// the viewer was already notified and will read the latest state
+		}
+	}
+}
+
+// Latest returns the json encoded state that was published last or nil if nothing was published yet
+func (b *Broadcaster) Latest() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.latest
+}
+
+// Viewers returns the number of connected viewers
+func (b *Broadcaster) Viewers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.viewers)
+}
+
+func (b *Broadcaster) subscribe() chan struct{} {
+	notify := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.viewers[notify] = struct{}{}
+
+	if b.latest != nil {
+		notify <- struct{}{}
+	}
+
+	return notify
+}
+
+func (b *Broadcaster) unsubscribe(notify chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.viewers, notify)
+}
+
+// Register adds the scoreboard page and its event stream to the given mux
+func (b *Broadcaster) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /scoreboard", b.servePage)
+	mux.HandleFunc("GET /scoreboard/events", b.serveEvents)
+}
+
+func (b *Broadcaster) servePage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if _, err := w.Write(page); err != nil {
+		b.log.Error("error writing scoreboard page", "error", err)
+	}
+}
+
+// serveEvents streams the game state as server-sent events until the viewer disconnects
+func (b *Broadcaster) serveEvents(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		b.log.Error("scoreboard events are not supported by the connection", "error", err)
+		return
+	}
+
+	notify := b.subscribe()
+	defer b.unsubscribe(notify)
+
+	b.log.Info("scoreboard viewer connected", "remote", r.RemoteAddr)
+	defer b.log.Info("scoreboard viewer disconnected", "remote", r.RemoteAddr)
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		var err error
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-notify:
+			_, err = fmt.Fprintf(w, "data: %s\n\n", b.Latest())
+		case <-ticker.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		}
+
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
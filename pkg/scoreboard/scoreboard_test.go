@@ -0,0 +1,150 @@
+package scoreboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*Broadcaster, *httptest.Server) {
+	b := NewBroadcaster(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mux := http.NewServeMux()
+	b.Register(mux)
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	return b, ts
+}
+
+// connect opens the event stream and returns the decoded states as they arrive
+func connect(t *testing.T, ts *httptest.Server) <-chan State {
+	resp, err := http.Get(ts.URL + "/scoreboard/events")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	states := make(chan State, 10)
+
+	go func() {
+		defer close(states)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			var s State
+			if err := json.Unmarshal([]byte(data), &s); err != nil {
+				return
+			}
+
+			states <- s
+		}
+	}()
+
+	return states
+}
+
+func receive(t *testing.T, states <-chan State) State {
+	select {
+	case s, ok := <-states:
+		require.True(t, ok, "event stream closed")
+		return s
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no state received")
+		return State{}
+	}
+}
+
+func waitForViewers(t *testing.T, b *Broadcaster, n int) {
+	require.Eventually(t, func() bool { return b.Viewers() == n }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBroadcaster_Events(t *testing.T) {
+	b, ts := newTestServer(t)
+
+	b.Publish(&State{Game: "301", Round: 1, Players: []PlayerState{{Name: "Alice", Remaining: 301, Current: true}}})
+
+	// a viewer connecting later receives the latest state right away
+	first := connect(t, ts)
+	s := receive(t, first)
+	assert.Equal(t, "301", s.Game)
+	assert.Equal(t, 301, s.Players[0].Remaining)
+
+	second := connect(t, ts)
+	assert.Equal(t, 1, receive(t, second).Round)
+
+	waitForViewers(t, b, 2)
+
+	b.Publish(&State{
+		Game:     "301",
+		Round:    2,
+		Players:  []PlayerState{{Name: "Alice", Remaining: 121, Current: true, Checkouts: []string{"T20 → T11 → D14"}}},
+		LastMove: &LastMoveState{Player: "Alice", Score: 180, Fields: []string{"T20", "T20", "T20"}},
+	})
+
+	for _, states := range []<-chan State{first, second} {
+		s := receive(t, states)
+		assert.Equal(t, 2, s.Round)
+		assert.Equal(t, 121, s.Players[0].Remaining)
+		assert.Equal(t, 180, s.LastMove.Score)
+	}
+}
+
+func TestBroadcaster_SlowViewerDoesNotBlock(t *testing.T) {
+	b := NewBroadcaster(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// a viewer that never reads its notifications
+	notify := b.subscribe()
+	defer b.unsubscribe(notify)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 100 {
+			b.Publish(&State{Round: i})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "publishing blocked")
+	}
+
+	<-notify
+
+	var s State
+	require.NoError(t, json.Unmarshal(b.Latest(), &s))
+	assert.Equal(t, 99, s.Round)
+}
+
+func TestBroadcaster_Page(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/scoreboard")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, string(body), "/scoreboard/events")
+}
@@ -0,0 +1,362 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/remote"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+)
+
+const (
+	// maxPageLimit and maxCheckoutLimit protect against expensive requests
+	maxPageLimit     = 500
+	maxCheckoutLimit = 20
+)
+
+type (
+	// Server serves the read paths of the datastore as json
+	Server struct {
+		log   *slog.Logger
+		ds    datastore.Datastore
+		board *scoreboard.Broadcaster
+		input *remote.Input
+		srv   *http.Server
+	}
+
+	gamesResponse struct {
+		Games []*datastore.GameStats `json:"games"`
+		Next  string                 `json:"next,omitempty"`
+	}
+
+	checkoutResponse struct {
+		Score int    `json:"score"`
+		Out   string `json:"out"`
+		Darts int    `json:"darts"`
+		// Checkouts contains the darts of every checkout variant, Variants their textual representation
+		Checkouts [][]string `json:"checkouts"`
+		Variants  []string   `json:"variants"`
+	}
+
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+
+	statusError struct {
+		status int
+		err    error
+	}
+)
+
+// New creates the server, the remote input is only served if it is not nil
+func New(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster, input *remote.Input) *Server {
+	s := &Server{
+		log:   log,
+		ds:    ds,
+		board: board,
+		input: input,
+	}
+
+	s.srv = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	return s
+}
+
+// Handler returns the routes of the api and the scoreboard, apart from the remote input only GET requests are served
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /games", s.handle(s.listGames))
+	mux.HandleFunc("GET /games/{id}", s.handle(s.getGame))
+	mux.HandleFunc("GET /players", s.handle(s.listPlayers))
+	mux.HandleFunc("GET /players/{id}/stats", s.handle(s.getPlayerStats))
+	mux.HandleFunc("GET /checkout/{score}", s.handle(s.getCheckout))
+
+	s.board.Register(mux)
+
+	if s.input != nil {
+		s.input.Register(mux)
+	}
+
+	return mux
+}
+
+// Start listens on the given address and serves the api in the background, it returns an error
+// right away if the address cannot be used
+func (s *Server) Start(address string) error {
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", address, err)
+	}
+
+	s.log.Info("serving http api", "address", ln.Addr().String())
+
+	go func() {
+		err := s.srv.Serve(ln)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("http api stopped", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *Server) Close() {
+	if err := s.srv.Close(); err != nil {
+		s.log.Error("error closing http api", "error", err)
+	}
+}
+
+// handle writes the result of a handler as json, errors are written as json object with the error message
+func (s *Server) handle(h func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			status = http.StatusOK
+			body   any
+		)
+
+		result, err := h(r)
+		if err != nil {
+			var se *statusError
+
+			switch {
+			case errors.As(err, &se):
+				status = se.status
+			case errors.Is(err, datastore.ErrNotFound):
+				status = http.StatusNotFound
+			default:
+				status = http.StatusInternalServerError
+			}
+
+			s.log.Error("error serving request", "path", r.URL.Path, "status", status, "error", err)
+
+			body = errorResponse{Error: err.Error()}
+		} else {
+			body = result
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(body); err != nil {
+			s.log.Error("error writing response", "path", r.URL.Path, "error", err)
+		}
+	}
+}
+
+func badRequest(format string, args ...any) error {
+	return &statusError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+// listGames returns all games or a page of games with the limit parameter, games can be filtered with the
+// filter parameter, e.g. /games?filter=player:Alice+days:30&limit=50
+func (s *Server) listGames(r *http.Request) (any, error) {
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := datastore.ParseFilters(r.URL.Query().Get("filter"), profiles, time.Now())
+	if err != nil {
+		return nil, badRequest("invalid filter: %w", err)
+	}
+
+	limitParam := r.URL.Query().Get("limit")
+	if limitParam == "" {
+		games, err := s.ds.ListGameStats(filters...)
+		if err != nil {
+			return nil, err
+		}
+
+		return gamesResponse{Games: nonNil(games)}, nil
+	}
+
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		return nil, badRequest("limit must be a number between 1 and %d", maxPageLimit)
+	}
+
+	page, err := s.ds.ListGameStatsPage(r.URL.Query().Get("after"), limit, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return gamesResponse{Games: nonNil(page.Games), Next: page.Next}, nil
+}
+
+func (s *Server) getGame(r *http.Request) (any, error) {
+	games, err := s.ds.ListGameStats(datastore.IdFilter(r.PathValue("id")))
+	if err != nil {
+		return nil, err
+	}
+
+	return games[0], nil
+}
+
+func (s *Server) listPlayers(_ *http.Request) (any, error) {
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	return nonNil(profiles), nil
+}
+
+// getPlayerStats computes the statistics of a player, optionally only from the games matching the filter parameter
+func (s *Server) getPlayerStats(r *http.Request) (any, error) {
+	id := r.PathValue("id")
+
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	names := datastore.ToPlayerNames(profiles)
+	if _, ok := names[id]; !ok {
+		return nil, fmt.Errorf("%w: player with id %q not found", datastore.ErrNotFound, id)
+	}
+
+	query := r.URL.Query().Get("filter")
+
+	stats, err := s.listPlayerStats(query, profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ps := range stats {
+		if ps.ID != id {
+			continue
+		}
+
+		if query == "" {
+			// the precomputed statistics do not contain the form
+			ps.Form, err = s.ds.GetPlayerForm(id)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ps, nil
+	}
+
+	// the player did not play any matching game
+	return &datastore.PlayerStats{
+		ID:          id,
+		Name:        names.Of(id),
+		RanksCount:  map[int]int{},
+		FieldsCount: map[string]int{},
+		Rating:      datastore.InitialRating,
+	}, nil
+}
+
+// listPlayerStats returns the precomputed player statistics, only with a filter they are computed from the matching games
+func (s *Server) listPlayerStats(query string, profiles []*datastore.PlayerProfile) ([]*datastore.PlayerStats, error) {
+	if query == "" {
+		return s.ds.ListPlayerStats()
+	}
+
+	filters, err := datastore.ParseFilters(query, profiles, time.Now())
+	if err != nil {
+		return nil, badRequest("invalid filter: %w", err)
+	}
+
+	games, err := s.ds.ListGameStats(filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return datastore.ToPlayerStats(games, datastore.ToPlayerNames(profiles))
+}
+
+// getCheckout returns checkout variants for a score, supported parameters are out, limit and darts,
+// e.g. /checkout/121?out=double&limit=3
+func (s *Server) getCheckout(r *http.Request) (any, error) {
+	score, err := strconv.Atoi(r.PathValue("score"))
+	if err != nil {
+		return nil, badRequest("unable to parse score: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	out := checkout.CheckoutTypeDoubleOut
+	if v := query.Get("out"); v != "" {
+		out, err = checkout.ParseCheckoutType(v)
+		if err != nil {
+			return nil, badRequest("%w", err)
+		}
+	}
+
+	intParam := func(name string, def, min, max int) (int, error) {
+		v := query.Get(name)
+		if v == "" {
+			return def, nil
+		}
+
+		i, err := strconv.Atoi(v)
+		if err != nil || i < min || i > max {
+			return 0, badRequest("%s must be a number between %d and %d", name, min, max)
+		}
+
+		return i, nil
+	}
+
+	limit, err := intParam("limit", 1, 1, maxCheckoutLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	darts, err := intParam("darts", 3, 1, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	res := checkoutResponse{
+		Score:     score,
+		Out:       string(out),
+		Darts:     darts,
+		Checkouts: [][]string{},
+		Variants:  []string{},
+	}
+
+	for _, cs := range checkout.For(score,
+		checkout.NewCalcLimitOption(limit),
+		checkout.NewCheckoutTypeOption(out),
+		checkout.NewMaxThrowsOption(darts),
+	) {
+		var scores []string
+		for _, sc := range cs.GetScores() {
+			scores = append(scores, sc.String())
+		}
+
+		res.Checkouts = append(res.Checkouts, scores)
+		res.Variants = append(res.Variants, cs.String())
+	}
+
+	return res, nil
+}
+
+// nonNil makes sure that empty lists are written as [] instead of null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
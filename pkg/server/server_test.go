@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, map[string]string) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ds, err := datastore.New(log, &config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(ds.Close)
+
+	require.NoError(t, ds.UpdateGameSettings(&datastore.GameSettings{
+		Type:     config.GameType301,
+		Checkout: checkout.CheckoutTypeDoubleOut,
+		Checkin:  checkout.CheckinTypeStraightIn,
+		Players:  []datastore.Player{{Name: "Alice"}, {Name: "Bob"}, {Name: "Claire"}},
+	}))
+
+	profiles, err := ds.ListPlayerProfiles()
+	require.NoError(t, err)
+
+	ids := map[string]string{}
+	for _, p := range profiles {
+		ids[p.Name] = p.ID
+	}
+
+	start := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	for i, id := range []string{"g1", "g2", "g3"} {
+		require.NoError(t, ds.CreateGameStats(&datastore.GameStats{
+			ID:       id,
+			GameType: config.GameType301,
+			Players:  []string{ids["Alice"], ids["Bob"]},
+			Ranks:    datastore.Ranks{1: ids["Alice"], 2: ids["Bob"]},
+			Start:    start.Add(time.Duration(i) * time.Hour),
+			Moves: []datastore.Move{
+				{Round: 1, Player: ids["Alice"], Score: datastore.Score{Total: 180, Fields: []string{"T20", "T20", "T20"}}, Remaining: 121, Duration: "2s"},
+				{Round: 1, Player: ids["Bob"], Score: datastore.Score{Total: 60, Fields: []string{"20", "20", "20"}}, Remaining: 241, Duration: "3s"},
+			},
+		}))
+	}
+
+	ts := httptest.NewServer(New(log, ds, scoreboard.NewBroadcaster(log), nil).Handler())
+	t.Cleanup(ts.Close)
+
+	return ts, ids
+}
+
+func get(t *testing.T, url string, into any) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(into))
+
+	return resp.StatusCode
+}
+
+func TestServer_Games(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	var games gamesResponse
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/games", &games))
+	assert.Len(t, games.Games, 3)
+	assert.Empty(t, games.Next)
+
+	var page gamesResponse
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/games?limit=2", &page))
+	require.Len(t, page.Games, 2)
+	require.NotEmpty(t, page.Next)
+
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/games?limit=2&after="+page.Next, &page))
+	require.Len(t, page.Games, 1)
+	assert.Equal(t, "g3", page.Games[0].ID)
+
+	var filtered gamesResponse
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/games?filter=player:Claire", &filtered))
+	assert.Empty(t, filtered.Games)
+
+	var game datastore.GameStats
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/games/g2", &game))
+	assert.Equal(t, "g2", game.ID)
+
+	var errResp errorResponse
+	assert.Equal(t, http.StatusNotFound, get(t, ts.URL+"/games/unknown", &errResp))
+	assert.Contains(t, errResp.Error, "not found")
+
+	assert.Equal(t, http.StatusBadRequest, get(t, ts.URL+"/games?filter=color:red", &errResp))
+	assert.Equal(t, `invalid filter: unknown filter "color"`, errResp.Error)
+}
+
+func TestServer_Players(t *testing.T) {
+	ts, ids := newTestServer(t)
+
+	var profiles []*datastore.PlayerProfile
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/players", &profiles))
+	assert.Len(t, profiles, 3)
+
+	var stats datastore.PlayerStats
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/players/"+ids["Alice"]+"/stats", &stats))
+	assert.Equal(t, "Alice", stats.Name)
+	assert.Equal(t, 3, stats.GamesPlayed)
+	assert.Equal(t, 3, stats.Scores180)
+	assert.Greater(t, stats.Rating, datastore.InitialRating)
+	assert.Len(t, stats.Form, 3)
+
+	stats = datastore.PlayerStats{}
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/players/"+ids["Alice"]+"/stats?filter=type:501", &stats))
+	assert.Equal(t, "Alice", stats.Name)
+	assert.Zero(t, stats.GamesPlayed)
+
+	stats = datastore.PlayerStats{}
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/players/"+ids["Alice"]+"/stats?filter=type:301", &stats))
+	assert.Equal(t, 3, stats.GamesPlayed)
+
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/players/"+ids["Claire"]+"/stats", &stats))
+	assert.Equal(t, "Claire", stats.Name)
+	assert.Zero(t, stats.GamesPlayed)
+
+	var errResp errorResponse
+	assert.Equal(t, http.StatusNotFound, get(t, ts.URL+"/players/unknown/stats", &errResp))
+}
+
+func TestServer_Checkout(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	var res checkoutResponse
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/checkout/121?out=double&limit=2", &res))
+	assert.Equal(t, checkoutResponse{
+		Score:     121,
+		Out:       "double-out",
+		Darts:     3,
+		Checkouts: [][]string{{"T20", "B", "D18"}, {"T20", "11", "DB"}},
+		Variants:  []string{"T20 → B → D18", "T20 → 11 → DB"},
+	}, res)
+
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/checkout/100?darts=1", &res))
+	assert.Empty(t, res.Checkouts)
+
+	var errResp errorResponse
+	assert.Equal(t, http.StatusBadRequest, get(t, ts.URL+"/checkout/40?out=triple", &errResp))
+	assert.Equal(t, "unknown check-out type: triple", errResp.Error)
+}
+
+func TestServer_ReadOnly(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	resp, err := http.Post(ts.URL+"/games", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -2,8 +2,12 @@ package common
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"unicode/utf8"
 
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -19,19 +23,24 @@ type (
 	SwitchViewMsg struct {
 		to View
 	}
+
+	UndoMoveMsg struct{}
 )
 
 const (
-	CloseGameDialogView View = "close-game-dialog"
-	DeleteGameStatView  View = "delete-game-stat-dialog"
-	GameDetailsView     View = "game-details"
-	GameListView        View = "game-list"
-	GameSettingsView    View = "game-settings"
-	GameView            View = "game"
-	MainMenuView        View = "main-menu"
-	PlayerDetailsView   View = "player-details"
-	PlayerListView      View = "player-list"
-	UndoMoveView        View = "undo-move-dialog"
+	CloseGameDialogView  View = "close-game-dialog"
+	DeleteGameStatView   View = "delete-game-stat-dialog"
+	GameDetailsView      View = "game-details"
+	GameListView         View = "game-list"
+	GameSettingsView     View = "game-settings"
+	GameView             View = "game"
+	HeadToHeadView       View = "head-to-head"
+	MainMenuView         View = "main-menu"
+	MergePlayersView     View = "merge-players-dialog"
+	PlayerDetailsView    View = "player-details"
+	PlayerListView       View = "player-list"
+	PlayerManagementView View = "player-management"
+	UndoMoveView         View = "undo-move-dialog"
 )
 
 const (
@@ -114,6 +123,58 @@ func (s *SwitchViewMsg) To() View {
 	return s.to
 }
 
+func UndoMove() tea.Msg {
+	return UndoMoveMsg{}
+}
+
+// ParseTurn parses the fields of a player's turn, separated by comma or space, e.g. "T20 20 D10".
+// A single number above 20 is taken as the total of the turn, in which case no fields are returned.
+func ParseTurn(input string) ([]*checkout.Score, int, error) {
+	var (
+		// allow both comma and space separated
+		segments = strings.Fields(strings.Join(strings.Split(strings.TrimSpace(input), ","), " "))
+		total    int
+		scores   []*checkout.Score
+	)
+
+	if len(segments) == 1 {
+		if t, err := strconv.Atoi(segments[0]); err == nil && t > 20 {
+			return nil, t, nil
+		}
+	}
+
+	switch len(segments) {
+	case 0:
+		return nil, 0, fmt.Errorf("no points entered")
+	case 1, 2, 3:
+		var (
+			sum   int
+			score *checkout.Score
+			err   error
+		)
+
+		for _, segment := range segments {
+			score, err = checkout.ParseScore(segment)
+			if err != nil {
+				break
+			}
+
+			sum += score.Value()
+			scores = append(scores, score)
+		}
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to parse input (%q), please enter again", err.Error())
+		}
+
+		total = sum
+	default:
+		return nil, 0, fmt.Errorf("no more than three throws are allowed, please enter again")
+	}
+
+	return scores, total, nil
+}
+
 func Fill(s string, length int) string {
 	for {
 		if utf8.RuneCountInString(s) < length {
@@ -199,3 +260,34 @@ func FormatBool(b bool) string {
 	}
 	return "no"
 }
+
+// Sparkline renders the values as a line of bars, only the last values are shown if they exceed the given width
+func Sparkline(values []float64, width int) string {
+	bars := []rune("▁▂▃▄▅▆▇█")
+
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	minVal, maxVal := values[0], values[0]
+	for _, v := range values {
+		minVal = min(minVal, v)
+		maxVal = max(maxVal, v)
+	}
+
+	var line []rune
+	for _, v := range values {
+		idx := len(bars) / 2
+		if maxVal > minVal {
+			idx = int((v - minVal) / (maxVal - minVal) * float64(len(bars)-1))
+		}
+
+		line = append(line, bars[idx])
+	}
+
+	return string(line)
+}
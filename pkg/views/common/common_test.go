@@ -0,0 +1,66 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTurn(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantFields int
+		wantTotal  int
+		wantErr    bool
+	}{
+		{
+			name:       "fields",
+			input:      "T20, 20 D10",
+			wantFields: 3,
+			wantTotal:  100,
+		},
+		{
+			name:       "single field",
+			input:      "20",
+			wantFields: 1,
+			wantTotal:  20,
+		},
+		{
+			name:      "total",
+			input:     "81",
+			wantTotal: 81,
+		},
+		{
+			name:    "too many fields",
+			input:   "1 2 3 4",
+			wantErr: true,
+		},
+		{
+			name:    "invalid field",
+			input:   "20 X",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores, total, err := ParseTurn(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, scores, tt.wantFields)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", Sparkline(nil, 10))
+	assert.Equal(t, "▅▅", Sparkline([]float64{3, 3}, 10))
+	assert.Equal(t, "▁▄█", Sparkline([]float64{0, 50, 100}, 10))
+	assert.Equal(t, "▁█", Sparkline([]float64{100, 0, 100}, 2))
+}
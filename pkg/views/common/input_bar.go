@@ -0,0 +1,108 @@
+package common
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InputBar is an input line below list views, e.g. for filter queries. The entered value is only taken over if it is valid.
+type InputBar struct {
+	label    string
+	input    textinput.Model
+	validate func(query string) error
+	query    string
+	editing  bool
+	err      error
+	// invalid is set when the submitted query is not valid anymore, e.g. when a player of a filter was renamed
+	invalid error
+}
+
+func NewInputBar(label, placeholder string, validate func(query string) error) *InputBar {
+	input := NewTextInput()
+	input.Width = 60
+	input.Placeholder = placeholder
+
+	return &InputBar{
+		label:    label,
+		input:    input,
+		validate: validate,
+	}
+}
+
+func NewFilterBar(validate func(query string) error) *InputBar {
+	return NewInputBar("Filter", "e.g. player:Alice type:501 days:30", validate)
+}
+
+// Edit starts editing the current query
+func (f *InputBar) Edit() tea.Cmd {
+	f.editing = true
+	f.err = nil
+	f.input.SetValue(f.query)
+	f.input.CursorEnd()
+
+	return tea.Batch(f.input.Focus(), f.input.Cursor.BlinkCmd())
+}
+
+func (f *InputBar) IsEditing() bool {
+	return f.editing
+}
+
+func (f *InputBar) Query() string {
+	return f.query
+}
+
+// Revalidate checks that the submitted query is still valid, the error is shown next to the query until a new one is submitted
+func (f *InputBar) Revalidate() error {
+	f.invalid = nil
+	if f.query != "" {
+		f.invalid = f.validate(f.query)
+	}
+
+	return f.invalid
+}
+
+// Update passes messages to the input and returns true when a new query was submitted
+func (f *InputBar) Update(msg tea.Msg) (bool, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && f.editing {
+		switch msg.String() {
+		case "esc":
+			f.editing = false
+			f.err = nil
+			return false, nil
+		case "enter":
+			query := f.input.Value()
+
+			if err := f.validate(query); err != nil {
+				f.err = err
+				return false, nil
+			}
+
+			f.query = query
+			f.editing = false
+			f.err = nil
+			f.invalid = nil
+
+			return true, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+
+	return false, cmd
+}
+
+func (f *InputBar) View() string {
+	switch {
+	case f.editing && f.err != nil:
+		return f.label + ": " + f.input.View() + " " + StyleError.Render(f.err.Error())
+	case f.editing:
+		return f.label + ": " + f.input.View()
+	case f.invalid != nil:
+		return StyleInactive.Render(f.label+": ") + StylePink.Render(f.query) + " " + StyleError.Render(f.invalid.Error())
+	case f.query != "":
+		return StyleInactive.Render(f.label+": ") + StylePink.Render(f.query)
+	default:
+		return StyleInactive.Render(f.label + ": none")
+	}
+}
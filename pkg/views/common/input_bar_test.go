@@ -0,0 +1,44 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputBar_Revalidate(t *testing.T) {
+	valid := map[string]bool{"player:Alice": true, "player:Bob": true}
+
+	f := NewFilterBar(func(query string) error {
+		if !valid[query] {
+			return errors.New("player not found")
+		}
+		return nil
+	})
+
+	require.NoError(t, f.Revalidate(), "an empty query is always valid")
+
+	f.Edit()
+	f.input.SetValue("player:Alice")
+	submitted, _ := f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, submitted)
+	require.NoError(t, f.Revalidate())
+
+	// the player was renamed after the filter was submitted
+	delete(valid, "player:Alice")
+
+	require.EqualError(t, f.Revalidate(), "player not found")
+	assert.Contains(t, f.View(), "player not found")
+	assert.Equal(t, "player:Alice", f.Query())
+
+	// a new query can still be entered
+	f.Edit()
+	f.input.SetValue("player:Bob")
+	submitted, _ = f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, submitted)
+	assert.NotContains(t, f.View(), "player not found")
+	require.NoError(t, f.Revalidate())
+}
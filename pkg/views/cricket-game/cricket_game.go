@@ -0,0 +1,643 @@
+package cricketgame
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+	"github.com/Gerrit91/darts-counter/pkg/cricket"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/player"
+	"github.com/Gerrit91/darts-counter/pkg/remote"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+	"github.com/Gerrit91/darts-counter/pkg/views/common"
+	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
+
+	"github.com/google/uuid"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type (
+	model struct {
+		log         *slog.Logger
+		ds          datastore.Datastore
+		broadcaster *scoreboard.Broadcaster
+		settings    *datastore.GameSettings
+
+		id            string
+		board         *cricket.Board
+		players       player.Players
+		currentPlayer *player.Player
+		start         time.Time
+		startMove     time.Time
+		iter          *player.Iterator
+		moves         []datastore.Move
+		err           error
+		msg           string
+		finished      bool
+
+		textInput   textinput.Model
+		help        help.Model
+		gameDetails *gamedetails.Model
+	}
+)
+
+func New(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster, show *gamedetails.Model) (*model, error) {
+	settings, err := ds.GetGameSettings()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve game settings: %w", err)
+	}
+
+	g, err := newModel(log, ds, board, show, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	g.checkpoint()
+
+	return g, nil
+}
+
+// Resume continues the cricket game of the last checkpoint by replaying its moves
+func Resume(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster, show *gamedetails.Model) (*model, error) {
+	cp, err := ds.GetCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve checkpoint: %w", err)
+	}
+
+	g, err := newModel(log, ds, board, show, &cp.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	g.id = cp.GameID
+	g.start = cp.Start
+
+	for _, m := range cp.Moves {
+		scores, err := parseFields(m.Score.Fields)
+		if err != nil {
+			return nil, err
+		}
+
+		g.tick(scores)
+		if g.err != nil {
+			return nil, fmt.Errorf("unable to replay previous move: %w", g.err)
+		}
+	}
+
+	if g.iter.GetRound() != cp.Round || g.CurrentPlayerID() != cp.Player {
+		return nil, fmt.Errorf("replayed game does not match the checkpoint (round %d, player %q)", cp.Round, cp.Player)
+	}
+
+	// the durations of the replayed moves are taken from the checkpoint
+	g.moves = cp.Moves
+	if !g.finished {
+		g.msg = "Resumed game from " + cp.Updated.Format(time.DateTime)
+	}
+	g.publish()
+
+	log.Info("resumed cricket game from checkpoint", "id", g.id, "moves", len(g.moves))
+
+	return g, nil
+}
+
+func newModel(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster, show *gamedetails.Model, settings *datastore.GameSettings) (*model, error) {
+	if settings.Type != config.GameTypeCricket {
+		return nil, fmt.Errorf("game type is not cricket: %s", settings.Type)
+	}
+
+	uuid, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate uuid: %w", err)
+	}
+
+	var players player.Players
+	for _, p := range settings.Players {
+		players = append(players, player.New(p.Name, settings.Checkout, settings.Checkin, 0).WithID(p.ID))
+	}
+
+	playerIterator := players.Iterator()
+	currentPlayer, err := playerIterator.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &model{
+		log:           log,
+		ds:            ds,
+		broadcaster:   board,
+		settings:      settings,
+		id:            uuid.String(),
+		board:         cricket.NewBoard(players.IDs()),
+		players:       players,
+		currentPlayer: currentPlayer,
+		start:         now,
+		startMove:     now,
+		iter:          playerIterator,
+		textInput:     common.NewTextInput(),
+		help:          common.NewHelp(),
+		gameDetails:   show,
+	}, nil
+}
+
+func (g *model) Init() tea.Cmd {
+	g.gameDetails.SetBackTo(common.SwitchViewTo(common.GameView))
+	return g.textInput.Cursor.BlinkCmd()
+}
+
+func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		g.help.Width = msg.Width
+		return g, nil
+	case cursor.BlinkMsg:
+		var cmd tea.Cmd
+		g.textInput, cmd = g.textInput.Update(msg)
+		return g, cmd
+	case remote.InputMsg:
+		g.err = nil
+		g.msg = ""
+
+		switch {
+		case msg.Action == remote.ActionUndo:
+			g.undo()
+		case g.finished:
+			g.err = fmt.Errorf("the game is finished, continue in the terminal")
+		case msg.Action == remote.ActionSkip:
+			g.tick(nil)
+			g.checkpoint()
+		default:
+			g.submit(msg.Input)
+		}
+
+		msg.Reply(g.err, g.msg, g.prompt())
+
+		return g, nil
+	case common.UndoMoveMsg:
+		g.undo()
+		return g, nil
+	case tea.KeyMsg:
+		g.err = nil
+		g.msg = ""
+
+		switch msg.String() {
+		case "q", "esc":
+			return g, common.SwitchViewTo(common.CloseGameDialogView)
+		case "v":
+			g.gameDetails.SetGameStats(*g.GameStats())
+			return g, common.SwitchViewTo(common.GameDetailsView)
+		case "u":
+			return g, common.SwitchViewTo(common.UndoMoveView)
+		case "s":
+			g.tick(nil)
+			g.checkpoint()
+			return g, nil
+		case "enter":
+			defer func() {
+				g.textInput.Reset()
+			}()
+
+			if g.finished {
+				if err := g.persist(); err != nil {
+					g.log.Error("error persisting finished game to database", "error", err)
+				}
+
+				g.deleteCheckpoint()
+
+				return g, common.SwitchViewTo(common.MainMenuView)
+			}
+
+			g.submit(g.textInput.Value())
+
+			return g, nil
+		default:
+			var cmd tea.Cmd
+			g.textInput, cmd = g.textInput.Update(msg)
+
+			return g, cmd
+		}
+	}
+
+	return g, nil
+}
+
+func (g *model) View() string {
+	var lines []string
+
+	lines = append(lines, common.Headline(fmt.Sprintf("Cricket: Round %d", g.iter.GetRound())), "")
+
+	headers := []string{"", "Player"}
+	for _, target := range cricket.Targets() {
+		headers = append(headers, checkout.NewScore(target).String())
+	}
+	headers = append(headers, "Points", "")
+
+	t := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
+		switch {
+		case col == 0:
+			return common.StylePink
+		case row == -1:
+			return common.StyleInactive
+		case col >= 2 && col < 2+len(cricket.Targets()) && g.board.IsClosed(cricket.Targets()[col-2]):
+			return common.StyleInactive
+		case col == 2+len(cricket.Targets()):
+			return common.StyleGreen
+		case g.currentPlayer != nil && g.players[row] == g.currentPlayer:
+			return common.StyleActive
+		default:
+			return common.StyleInactive
+		}
+	}).Headers(headers...)
+
+	for _, p := range g.players {
+		currentPlayerArrow := ""
+		if g.currentPlayer != nil && p == g.currentPlayer {
+			currentPlayerArrow = "→"
+		}
+		if p.GetRank() > 0 {
+			currentPlayerArrow = strconv.Itoa(p.GetRank()) + "."
+		}
+
+		row := []string{currentPlayerArrow, p.GetName()}
+		for _, target := range cricket.Targets() {
+			row = append(row, marksSymbol(g.board.Marks(p.GetID(), target)))
+		}
+
+		lastMove := ""
+		for _, m := range slices.Backward(g.moves) {
+			if m.Player == p.GetID() {
+				lastMove = common.StylePink.Render(fmt.Sprintf("(+%d)", m.Score.Total))
+				break
+			}
+		}
+
+		row = append(row, strconv.Itoa(g.board.Points(p.GetID())), lastMove)
+
+		t.Row(row...)
+	}
+
+	lines = append(lines, t.Render(), "")
+
+	if g.err != nil {
+		lines = append(lines, common.StyleError.Render(g.err.Error()))
+	}
+	if g.msg != "" {
+		lines = append(lines, g.msg)
+	}
+
+	if g.finished {
+		lines = append(lines, "Game finished.")
+		lines = append(lines, g.help.ShortHelpView([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "return to main menu"),
+			),
+			key.NewBinding(
+				key.WithKeys("u"),
+				key.WithHelp("u", "undo last move"),
+			),
+			key.NewBinding(
+				key.WithKeys("v"),
+				key.WithHelp("v", "view move history"),
+			),
+			key.NewBinding(
+				key.WithKeys("q", "esc"),
+				key.WithHelp("q", "quit"),
+			),
+		}))
+	} else {
+		lines = append(lines, g.prompt())
+		lines = append(lines, g.textInput.View())
+		lines = append(lines, g.help.ShortHelpView([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("s"),
+				key.WithHelp("s", "skip player"),
+			),
+			key.NewBinding(
+				key.WithKeys("u"),
+				key.WithHelp("u", "undo last move"),
+			),
+			key.NewBinding(
+				key.WithKeys("v"),
+				key.WithHelp("v", "view move history"),
+			),
+			key.NewBinding(
+				key.WithKeys("q", "esc"),
+				key.WithHelp("q", "quit"),
+			),
+		}))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// submit enters the fields of a turn
+func (g *model) submit(input string) {
+	scores, _, err := common.ParseTurn(input)
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	if len(scores) == 0 {
+		g.err = fmt.Errorf("cricket requires the fields of the turn, please enter again")
+		return
+	}
+
+	g.tick(scores)
+	g.checkpoint()
+}
+
+// undo reverts the last move by replaying the moves before it
+func (g *model) undo() {
+	if len(g.moves) == 0 {
+		g.err = fmt.Errorf("cannot go back any further, no previous moves")
+		return
+	}
+
+	lastIdx := len(g.moves) - 1
+	lastMove := g.moves[lastIdx]
+
+	lastPlayer, err := g.iter.SetBackTo(lastMove.Player)
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	board, err := replay(g.players.IDs(), g.moves[:lastIdx])
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	for _, p := range g.players {
+		p.SetRank(0)
+	}
+
+	g.board = board
+	g.finished = false
+	g.moves = g.moves[:lastIdx]
+	g.currentPlayer = lastPlayer
+	g.checkpoint()
+}
+
+func (g *model) prompt() string {
+	if g.finished {
+		return ""
+	}
+
+	return "Enter fields:"
+}
+
+func (g *model) tick(scores []*checkout.Score) {
+	if g.finished {
+		return
+	}
+
+	p := g.currentPlayer
+
+	turn, err := g.board.Throw(p.GetID(), scores)
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	statsScore := datastore.Score{
+		Total: turn.Points,
+	}
+	for _, score := range scores {
+		statsScore.Fields = append(statsScore.Fields, score.String())
+	}
+
+	since := time.Since(g.startMove)
+	g.startMove = g.startMove.Add(since)
+
+	g.moves = append(g.moves, datastore.Move{
+		Round:     g.iter.GetRound(),
+		Player:    p.GetID(),
+		Score:     statsScore,
+		Remaining: g.board.Points(p.GetID()),
+		Duration:  since.String(),
+		Marks:     turn.Marks,
+	})
+
+	if winner, ok := g.board.Winner(); ok {
+		winnerName := winner
+		for rank, id := range g.board.Ranking() {
+			for _, p := range g.players {
+				if p.GetID() == id {
+					p.SetRank(rank + 1)
+				}
+				if p.GetID() == winner {
+					winnerName = p.GetName()
+				}
+			}
+		}
+
+		g.msg = fmt.Sprintf("%s won the game!", winnerName)
+		g.currentPlayer = nil
+		g.finished = true
+
+		return
+	}
+
+	p, err = g.iter.Next()
+	g.currentPlayer = p
+
+	if errors.Is(err, player.ErrGameFinished) {
+		// a single player plays until all targets are closed, so this should not happen
+		g.finished = true
+		return
+	}
+
+	if err != nil {
+		g.err = err
+		return
+	}
+}
+
+func (g *model) persist() error {
+	if !g.settings.SaveGameToStats {
+		g.log.Info("not saving game to database because disabled in game settings")
+		return nil
+	}
+
+	err := g.ds.CreateGameStats(g.GameStats())
+	if err != nil {
+		return err
+	}
+
+	g.log.Info("saved game stats to database")
+
+	return nil
+}
+
+// checkpoint stores the state of the running game, so that it can be resumed after a crash or quit,
+// and publishes it to the scoreboard
+func (g *model) checkpoint() {
+	g.publish()
+
+	ranks := datastore.Ranks{}
+	for _, p := range g.players {
+		if p.GetRank() > 0 {
+			ranks[p.GetRank()] = p.GetID()
+		}
+	}
+
+	err := g.ds.UpdateCheckpoint(&datastore.Checkpoint{
+		Settings: *g.settings,
+		GameID:   g.id,
+		Start:    g.start,
+		Moves:    g.moves,
+		Round:    g.iter.GetRound(),
+		Player:   g.CurrentPlayerID(),
+		Ranks:    ranks,
+		Updated:  time.Now(),
+	})
+	if err != nil {
+		g.log.Error("error storing checkpoint of running game", "error", err)
+	}
+}
+
+// publish shows the points of the players on the scoreboard, the targets are the ones a player has not closed yet
+func (g *model) publish() {
+	state := &scoreboard.State{
+		Game:     string(g.settings.Type),
+		Round:    g.iter.GetRound(),
+		Message:  g.msg,
+		Finished: g.finished,
+		Updated:  time.Now(),
+	}
+
+	for _, p := range g.players {
+		var open []string
+		for _, target := range cricket.Targets() {
+			if g.board.Marks(p.GetID(), target) < 3 {
+				open = append(open, checkout.NewScore(target).String())
+			}
+		}
+
+		state.Players = append(state.Players, scoreboard.PlayerState{
+			ID:        p.GetID(),
+			Name:      p.GetName(),
+			Remaining: g.board.Points(p.GetID()),
+			Target:    strings.Join(open, " "),
+			Rank:      p.GetRank(),
+			Current:   g.currentPlayer != nil && p == g.currentPlayer,
+		})
+	}
+
+	if len(g.moves) > 0 {
+		last := g.moves[len(g.moves)-1]
+		state.LastMove = &scoreboard.LastMoveState{
+			Score:  last.Score.Total,
+			Fields: last.Score.Fields,
+		}
+		for _, p := range g.players {
+			if p.GetID() == last.Player {
+				state.LastMove.Player = p.GetName()
+			}
+		}
+	}
+
+	g.broadcaster.Publish(state)
+}
+
+func (g *model) deleteCheckpoint() {
+	err := g.ds.DeleteCheckpoint()
+	if err != nil {
+		g.log.Error("error deleting checkpoint of finished game", "error", err)
+	}
+}
+
+// CurrentPlayerID returns the ID of the player whose turn it is, it is empty when the game is finished
+func (g *model) CurrentPlayerID() string {
+	if g.currentPlayer == nil {
+		return ""
+	}
+
+	return g.currentPlayer.GetID()
+}
+
+// GameStats returns the statistics of the running game
+func (g *model) GameStats() *datastore.GameStats {
+	ranks := map[int]string{}
+	if g.finished {
+		for _, p := range g.players {
+			ranks[p.GetRank()] = p.GetID()
+		}
+	}
+
+	return &datastore.GameStats{
+		ID:       g.id,
+		GameType: config.GameTypeCricket,
+		Players:  g.players.IDs(),
+		Rounds:   g.iter.GetRound(),
+		Ranks:    ranks,
+		Start:    g.start,
+		End:      time.Now(),
+		Moves:    g.moves,
+	}
+}
+
+// replay rebuilds the board from the given moves
+func replay(players []string, moves []datastore.Move) (*cricket.Board, error) {
+	board := cricket.NewBoard(players)
+
+	for _, m := range moves {
+		scores, err := parseFields(m.Score.Fields)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = board.Throw(m.Player, scores)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return board, nil
+}
+
+// parseFields parses the fields of a previous move
+func parseFields(fields []string) ([]*checkout.Score, error) {
+	var scores []*checkout.Score
+
+	for _, field := range fields {
+		score, err := checkout.ParseScore(field)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse field of previous move: %w", err)
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
+
+func marksSymbol(marks int) string {
+	switch marks {
+	case 0:
+		return ""
+	case 1:
+		return "/"
+	case 2:
+		return "X"
+	default:
+		return "⊗"
+	}
+}
@@ -0,0 +1,223 @@
+package cricketgame
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+	"github.com/Gerrit91/darts-counter/pkg/cricket"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/remote"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+	"github.com/Gerrit91/darts-counter/pkg/views/common"
+	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDatastore(t *testing.T) datastore.Datastore {
+	ds, err := datastore.New(slog.New(slog.NewTextHandler(io.Discard, nil)), &config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(ds.Close)
+
+	require.NoError(t, ds.UpdateGameSettings(&datastore.GameSettings{
+		Type:     config.GameTypeCricket,
+		Checkout: checkout.CheckoutTypeDoubleOut,
+		Checkin:  checkout.CheckinTypeStraightIn,
+		Players:  []datastore.Player{{Name: "Alice"}, {Name: "Bob"}},
+	}))
+
+	return ds
+}
+
+func newTestGame(t *testing.T, ds datastore.Datastore, board *scoreboard.Broadcaster) *model {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	g, err := New(log, ds, board, gamedetails.New(log, ds))
+	require.NoError(t, err)
+
+	return g
+}
+
+func resume(t *testing.T, ds datastore.Datastore) *model {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	g, err := Resume(log, ds, scoreboard.NewBroadcaster(log), gamedetails.New(log, ds))
+	require.NoError(t, err)
+
+	return g
+}
+
+// play enters the given turns one after another, the input s skips a turn
+func play(t *testing.T, g *model, inputs ...string) {
+	for _, input := range inputs {
+		if input == "s" {
+			g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+			continue
+		}
+
+		g.textInput.SetValue(input)
+		g.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		require.NoError(t, g.err, "input %q", input)
+	}
+}
+
+func assertResumed(t *testing.T, want, got *model) {
+	assert.Equal(t, want.id, got.id)
+	assert.Equal(t, want.CurrentPlayerID(), got.CurrentPlayerID())
+	assert.Equal(t, want.iter.GetRound(), got.iter.GetRound())
+	assert.Equal(t, want.finished, got.finished)
+	assert.Equal(t, withoutDurations(want.moves), withoutDurations(got.moves))
+
+	require.Len(t, got.players, len(want.players))
+	for i, p := range want.players {
+		assert.Equal(t, p.GetID(), got.players[i].GetID())
+		assert.Equal(t, p.GetRank(), got.players[i].GetRank(), "rank of %s", p.GetName())
+		assert.Equal(t, want.board.Points(p.GetID()), got.board.Points(p.GetID()), "points of %s", p.GetName())
+
+		for _, target := range cricket.Targets() {
+			assert.Equal(t, want.board.Marks(p.GetID(), target), got.board.Marks(p.GetID(), target), "marks of %s on %d", p.GetName(), target)
+		}
+	}
+}
+
+// withoutDurations removes the durations of moves, which differ between games that are played at different times
+func withoutDurations(moves []datastore.Move) []datastore.Move {
+	var res []datastore.Move
+	for _, m := range moves {
+		m.Duration = ""
+		res = append(res, m)
+	}
+	return res
+}
+
+func TestResume(t *testing.T) {
+	ds := newTestDatastore(t)
+
+	// a checkpoint of a previous x01 game is replaced when a cricket game starts
+	require.NoError(t, ds.UpdateCheckpoint(&datastore.Checkpoint{
+		Settings: datastore.GameSettings{Type: config.GameType301},
+		GameID:   "stale",
+	}))
+
+	g := newTestGame(t, ds, scoreboard.NewBroadcaster(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	cp, err := ds.GetCheckpoint()
+	require.NoError(t, err)
+	assert.Equal(t, config.GameTypeCricket, cp.Settings.Type)
+	assert.Equal(t, g.id, cp.GameID)
+
+	play(t, g,
+		"T20 T20",
+		"T19 19",
+		"s",
+		"T18 D17 M",
+	)
+
+	r := resume(t, ds)
+	assertResumed(t, g, r)
+	assert.Equal(t, 60, r.board.Points(r.players[0].GetID()))
+
+	// the resumed game goes on like the original one
+	play(t, g, "20 20")
+	play(t, r, "20 20")
+	assertResumed(t, g, r)
+
+	t.Run("undo", func(t *testing.T) {
+		g.Update(common.UndoMoveMsg{})
+		require.NoError(t, g.err)
+
+		assertResumed(t, g, resume(t, ds))
+	})
+
+	t.Run("finished game", func(t *testing.T) {
+		play(t, g,
+			"T19",
+			"s",
+			"T18 T17 T16",
+			"s",
+			"T15 DB B",
+		)
+		require.True(t, g.finished)
+		assert.Equal(t, 1, g.players[0].GetRank())
+
+		assertResumed(t, g, resume(t, ds))
+
+		g.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		_, err := ds.GetCheckpoint()
+		assert.ErrorIs(t, err, datastore.ErrNotFound)
+	})
+}
+
+func TestPublish(t *testing.T) {
+	board := scoreboard.NewBroadcaster(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	g := newTestGame(t, newTestDatastore(t), board)
+
+	latest := func() *scoreboard.State {
+		var state scoreboard.State
+		require.NoError(t, json.Unmarshal(board.Latest(), &state))
+		return &state
+	}
+
+	state := latest()
+	assert.Equal(t, "cricket", state.Game)
+	require.Len(t, state.Players, 2)
+	assert.True(t, state.Players[0].Current)
+	assert.Nil(t, state.LastMove)
+
+	play(t, g, "T20 T20")
+
+	state = latest()
+	assert.Equal(t, 60, state.Players[0].Remaining)
+	assert.Equal(t, "19 18 17 16 15 B", state.Players[0].Target)
+	assert.True(t, state.Players[1].Current)
+	assert.Equal(t, &scoreboard.LastMoveState{Player: "Alice", Score: 60, Fields: []string{"T20", "T20"}}, state.LastMove)
+
+	g.Update(common.UndoMoveMsg{})
+
+	state = latest()
+	assert.Zero(t, state.Players[0].Remaining)
+	assert.True(t, state.Players[0].Current)
+	assert.Nil(t, state.LastMove)
+}
+
+func TestUpdate_RemoteInput(t *testing.T) {
+	g := newTestGame(t, newTestDatastore(t), scoreboard.NewBroadcaster(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	send := func(action remote.Action, input string) {
+		g.Update(remote.NewInputMsg(action, input))
+	}
+
+	send(remote.ActionScore, "T20 T20")
+	require.NoError(t, g.err)
+	require.Len(t, g.moves, 1)
+	assert.Equal(t, 60, g.moves[0].Remaining)
+
+	send(remote.ActionSkip, "")
+	require.NoError(t, g.err)
+	require.Len(t, g.moves, 2)
+	assert.Empty(t, g.moves[1].Score.Fields)
+
+	send(remote.ActionScore, "60")
+	require.EqualError(t, g.err, "cricket requires the fields of the turn, please enter again")
+	assert.Len(t, g.moves, 2)
+
+	send(remote.ActionUndo, "")
+	require.NoError(t, g.err)
+	assert.Len(t, g.moves, 1)
+	assert.Equal(t, g.players[1], g.currentPlayer)
+
+	play(t, g, "s", "T19 T18 T17", "s", "T16 T15 DB", "s", "B")
+	require.True(t, g.finished)
+
+	send(remote.ActionScore, "T20")
+	require.EqualError(t, g.err, "the game is finished, continue in the terminal")
+	assert.Empty(t, g.prompt())
+}
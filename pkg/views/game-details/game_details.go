@@ -8,7 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
 	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/player"
 	"github.com/Gerrit91/darts-counter/pkg/views/common"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -22,7 +25,8 @@ type Model struct {
 	log *slog.Logger
 	ds  datastore.Datastore
 
-	gs datastore.GameStats
+	gs    datastore.GameStats
+	names datastore.PlayerNames
 
 	viewport viewport.Model
 	help     help.Model
@@ -85,8 +89,22 @@ func (s *Model) View() string {
 		return common.StyleActive
 	})
 	t1.Row("ID:", gs.ID)
-	t1.Row("Type:", fmt.Sprintf("%s (%s, %s)", gs.GameType, gs.Checkin, gs.Checkout))
-	t1.Row("Players: ", strings.Join(s.gs.Players, ", "))
+	if gs.GameType == config.GameTypeCricket || gs.GameType == config.GameTypeAroundTheClock {
+		t1.Row("Type:", string(gs.GameType))
+	} else {
+		t1.Row("Type:", fmt.Sprintf("%s (%s, %s)", gs.GameName(), gs.Checkin, gs.Checkout))
+	}
+	t1.Row("Players: ", strings.Join(s.names.All(s.gs.Players), ", "))
+	if len(gs.StartScores) > 0 {
+		var startScores []string
+		for _, p := range gs.Players {
+			startScores = append(startScores, fmt.Sprintf("%s: %d", s.names.Of(p), gs.StartScoreOf(p)))
+		}
+		t1.Row("Start Scores:", strings.Join(startScores, ", "))
+	}
+	if gs.MatchID != "" {
+		t1.Row("Match:", fmt.Sprintf("%s (Set %d, Leg %d)", gs.MatchID, gs.Set, gs.Leg))
+	}
 	viewportLines = append(viewportLines, t1.Render())
 
 	t2 := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
@@ -99,6 +117,17 @@ func (s *Model) View() string {
 	t2.Row("Start:", gs.Start.Format(time.DateTime))
 	t2.Row("End:", gs.End.Format(time.DateTime))
 	t2.Row("Length:", gs.End.Sub(gs.Start).Truncate(time.Millisecond).String())
+	if gs.GameType != config.GameTypeCricket && gs.GameType != config.GameTypeAroundTheClock {
+		var checkouts []string
+		for _, p := range gs.Players {
+			if darts := gs.LegDarts(p); darts > 0 {
+				checkouts = append(checkouts, fmt.Sprintf("%s (%d-darter)", s.names.Of(p), darts))
+			}
+		}
+		if len(checkouts) > 0 {
+			t2.Row("Checkout:", strings.Join(checkouts, ", "))
+		}
+	}
 	viewportLines = append(viewportLines, t2.Render(), "")
 
 	viewportLines = append(viewportLines, common.StyleInactive.Render("Ranks:"))
@@ -114,7 +143,7 @@ func (s *Model) View() string {
 	for k, v := range s.gs.Ranks {
 		ranks = append(ranks, rank{
 			rank:   k,
-			player: v,
+			player: s.names.Of(v),
 		})
 		ranksColors[k] = ""
 	}
@@ -133,14 +162,39 @@ func (s *Model) View() string {
 
 	viewportLines = append(viewportLines, "Moves:")
 
-	t3 := common.NewTable().Headers(
+	headers := []string{
 		"Round",
 		"Player",
 		"Score",
 		"Fields",
+		"Darts",
 		"Remaining",
 		"Duration",
-	).StyleFunc(func(row, col int) lipgloss.Style {
+	}
+	switch gs.GameType {
+	case config.GameTypeAroundTheClock:
+		headers = []string{
+			"Round",
+			"Player",
+			"Advanced",
+			"Fields",
+			"Target",
+			"Darts",
+			"Duration",
+		}
+	case config.GameTypeCricket:
+		headers = []string{
+			"Round",
+			"Player",
+			"Points",
+			"Marks",
+			"Fields",
+			"Total",
+			"Duration",
+		}
+	}
+
+	t3 := common.NewTable().Headers(headers...).StyleFunc(func(row, col int) lipgloss.Style {
 		switch {
 		case row == -1:
 			return common.StyleInactive
@@ -156,11 +210,52 @@ func (s *Model) View() string {
 			duration = d.Truncate(time.Millisecond).String()
 		}
 
+		switch gs.GameType {
+		case config.GameTypeAroundTheClock:
+			target := "—"
+			if targets := player.AroundTheClockTargets(); move.Remaining > 0 && move.Remaining <= len(targets) {
+				target = checkout.NewScore(targets[len(targets)-move.Remaining]).String()
+			}
+
+			t3 = t3.Row(
+				strconv.Itoa(move.Round),
+				s.names.Of(move.Player),
+				common.StylePink.Render("+"+strconv.Itoa(move.Score.Total)),
+				strings.Join(move.Score.Fields, " → "),
+				common.StyleGreen.Render(target),
+				strconv.Itoa(move.Darts),
+				duration,
+			)
+			continue
+		case config.GameTypeCricket:
+			t3 = t3.Row(
+				strconv.Itoa(move.Round),
+				s.names.Of(move.Player),
+				common.StylePink.Render("+"+strconv.Itoa(move.Score.Total)),
+				strconv.Itoa(move.Marks),
+				strings.Join(move.Score.Fields, " → "),
+				common.StyleGreen.Render(strconv.Itoa(move.Remaining)),
+				duration,
+			)
+			continue
+		}
+
+		darts := "—"
+		if move.Darts > 0 {
+			darts = strconv.Itoa(move.Darts)
+		}
+
+		score := common.StylePink.Render("—" + strconv.Itoa(move.Score.Total))
+		if move.Bust {
+			score = common.StyleError.Render("bust")
+		}
+
 		t3 = t3.Row(
 			strconv.Itoa(move.Round),
-			move.Player,
-			fmt.Sprintf("%s (%s)", common.StylePink.Render("—"+strconv.Itoa(move.Score.Total)), common.StyleGreen.Render(strconv.Itoa(move.Remaining+move.Score.Total))),
+			s.names.Of(move.Player),
+			fmt.Sprintf("%s (%s)", score, common.StyleGreen.Render(strconv.Itoa(move.Remaining+move.Score.Total))),
 			strings.Join(move.Score.Fields, " → "),
+			darts,
 			strconv.Itoa(move.Remaining),
 			duration,
 		)
@@ -204,4 +299,11 @@ func (s *Model) SetBackTo(cmd tea.Cmd) {
 
 func (s *Model) SetGameStats(gs datastore.GameStats) {
 	s.gs = gs
+
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		s.log.Error("unable to fetch player profiles", "error", err)
+	}
+
+	s.names = datastore.ToPlayerNames(profiles)
 }
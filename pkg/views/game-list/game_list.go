@@ -2,7 +2,9 @@ package gamelist
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
 	"strings"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/Gerrit91/darts-counter/pkg/views/common"
 	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
 
+	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -17,6 +20,12 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+const (
+	pageSize = 50
+	// pagePreload is the distance of the cursor to the end of the list at which the next page is loaded
+	pagePreload = 10
+)
+
 type (
 	model struct {
 		log *slog.Logger
@@ -25,7 +34,16 @@ type (
 		gameDetails *gamedetails.Model
 		cursor      int
 		stats       []*datastore.GameStats
+		next        string
+		loadPage    func(after string) (*datastore.GameStatsPage, error)
+		listAll     func() ([]*datastore.GameStats, error)
+		names       datastore.PlayerNames
 		toDelete    *datastore.GameStats
+		filter      *common.InputBar
+		importBar   *common.InputBar
+		message     string
+		// failure is shown instead of the message when an export or import failed, the list stays usable
+		failure error
 
 		viewport viewport.Model
 		help     help.Model
@@ -40,23 +58,72 @@ func DeleteGameStat() tea.Msg {
 }
 
 func New(log *slog.Logger, ds datastore.Datastore, gameDetails *gamedetails.Model) *model {
-	return &model{
+	s := &model{
 		log:         log,
 		viewport:    viewport.New(0, 20),
 		ds:          ds,
 		gameDetails: gameDetails,
 		help:        common.NewHelp(),
 	}
+
+	s.filter = common.NewFilterBar(func(query string) error {
+		profiles, err := s.ds.ListPlayerProfiles()
+		if err != nil {
+			return err
+		}
+
+		_, err = datastore.ParseFilters(query, profiles, time.Now())
+
+		return err
+	})
+
+	s.importBar = common.NewInputBar("Import", "path to a json archive", func(path string) error {
+		_, err := readArchive(path)
+		return err
+	})
+
+	return s
 }
 
 func (s *model) Init() tea.Cmd {
-	var err error
-	s.stats, err = s.ds.ListGameStats()
+	s.err = nil
+
+	if err := s.filter.Revalidate(); err != nil {
+		s.stats = nil
+		s.next = ""
+		s.cursor = 0
+		return nil
+	}
+
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		s.err = err
+		return nil
+	}
+
+	s.names = datastore.ToPlayerNames(profiles)
+
+	filters, err := datastore.ParseFilters(s.filter.Query(), profiles, time.Now())
 	if err != nil {
 		s.err = err
 		return nil
 	}
 
+	s.loadPage = func(after string) (*datastore.GameStatsPage, error) {
+		return s.ds.ListGameStatsPage(after, pageSize, filters...)
+	}
+	s.listAll = func() ([]*datastore.GameStats, error) {
+		return s.ds.ListGameStats(filters...)
+	}
+
+	page, err := s.loadPage("")
+	if err != nil {
+		s.err = err
+		return nil
+	}
+
+	s.stats = page.Games
+	s.next = page.Next
 	s.cursor = 0
 	s.viewport.GotoTop()
 
@@ -67,6 +134,30 @@ func (s *model) Init() tea.Cmd {
 	return tea.WindowSize()
 }
 
+// loadMore appends the next page of games to the list, if there is any
+func (s *model) loadMore() {
+	if s.next == "" {
+		return
+	}
+
+	page, err := s.loadPage(s.next)
+	if err != nil {
+		s.err = err
+		return
+	}
+
+	s.stats = append(s.stats, page.Games...)
+	s.next = page.Next
+
+	s.log.Info("fetched next page of games from database", "entries", len(page.Games))
+}
+
+func (s *model) loadAll() {
+	for s.next != "" && s.err == nil {
+		s.loadMore()
+	}
+}
+
 func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case deleteGameStatMsg:
@@ -85,11 +176,48 @@ func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return s, s.Init()
 	case tea.WindowSizeMsg:
-		common.AdjustViewportResize(&s.viewport, msg, s.cursor, 2, 1)
+		common.AdjustViewportResize(&s.viewport, msg, s.cursor, 2, 2)
+	case cursor.BlinkMsg:
+		_, filterCmd := s.filter.Update(msg)
+		_, importCmd := s.importBar.Update(msg)
+		return s, tea.Batch(filterCmd, importCmd)
 	case tea.KeyMsg:
+		if s.filter.IsEditing() {
+			submitted, cmd := s.filter.Update(msg)
+			if submitted {
+				return s, s.Init()
+			}
+			return s, cmd
+		}
+
+		if s.importBar.IsEditing() {
+			submitted, cmd := s.importBar.Update(msg)
+			if submitted {
+				return s, s.importArchive()
+			}
+			return s, cmd
+		}
+
+		s.message = ""
+		s.failure = nil
+
 		switch msg.String() {
 		case "q", "esc":
 			return s, common.SwitchViewTo(common.MainMenuView)
+		case "/":
+			return s, s.filter.Edit()
+		case "i":
+			return s, s.importBar.Edit()
+		}
+
+		if len(s.stats) == 0 {
+			return s, nil
+		}
+
+		switch msg.String() {
+		case "x":
+			s.export()
+			return s, nil
 		case "d", "delete":
 			s.toDelete = s.stats[s.cursor]
 			return s, common.SwitchViewTo(common.DeleteGameStatView)
@@ -99,6 +227,9 @@ func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return s, common.SwitchViewTo(common.GameDetailsView)
 		case "down":
 			s.cursor++
+			if s.cursor >= len(s.stats)-pagePreload {
+				s.loadMore()
+			}
 			if s.cursor >= len(s.stats) {
 				s.cursor = 0
 				_ = s.viewport.GotoTop()
@@ -106,6 +237,7 @@ func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "up":
 			s.cursor--
 			if s.cursor < 0 {
+				s.loadAll()
 				s.cursor = len(s.stats) - 1
 				s.viewport.GotoBottom()
 			}
@@ -113,6 +245,7 @@ func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			s.cursor = 0
 			s.viewport.GotoTop()
 		case "G":
+			s.loadAll()
 			s.cursor = len(s.stats) - 1
 			s.viewport.GotoBottom()
 		}
@@ -121,6 +254,11 @@ func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	s.viewport, cmd = s.viewport.Update(msg)
 
+	// the viewport can also be scrolled without moving the cursor, e.g. with page down
+	if s.viewport.AtBottom() && s.viewport.TotalLineCount() > 0 {
+		s.loadMore()
+	}
+
 	return s, cmd
 }
 
@@ -129,17 +267,21 @@ func (s *model) View() string {
 		lines []string
 		row   = func(stat *datastore.GameStats) []string {
 			var players []string
-			players = append(players, stat.Players...)
-			for i, p := range players {
-				players[i] = fmt.Sprintf("%s (%d.)", p, stat.Ranks.OfPlayer(p))
+			for _, p := range stat.Players {
+				players = append(players, fmt.Sprintf("%s (%d.)", s.names.Of(p), stat.Ranks.OfPlayer(p)))
+			}
+
+			game := stat.GameName()
+			if stat.MatchID != "" {
+				game = fmt.Sprintf("%s (S%d/L%d)", game, stat.Set, stat.Leg)
 			}
 
 			return []string{
 				stat.Start.Format("02.01.2006"),
 				stat.Start.Format(time.TimeOnly),
-				string(stat.GameType),
+				game,
 				stat.End.Sub(stat.Start).Truncate(time.Second).String(),
-				stat.Ranks[1],
+				s.names.Of(stat.Ranks[1]),
 				strings.Join(players, ", "),
 			}
 		}
@@ -195,6 +337,47 @@ func (s *model) View() string {
 
 	lines = append(lines, common.Headline("Game Statistics"))
 	lines = append(lines, s.viewport.View())
+
+	if s.importBar.IsEditing() {
+		lines = append(lines, s.importBar.View())
+		lines = append(lines, s.help.ShortHelpView([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "import"),
+			),
+			key.NewBinding(
+				key.WithKeys("esc"),
+				key.WithHelp("esc", "cancel"),
+			),
+		}))
+
+		return strings.Join(lines, "\n")
+	}
+
+	switch {
+	case s.failure != nil:
+		lines = append(lines, s.filter.View()+"  "+common.StyleError.Render(s.failure.Error()))
+	case s.message != "":
+		lines = append(lines, s.filter.View()+"  "+common.StyleGreen.Render(s.message))
+	default:
+		lines = append(lines, s.filter.View())
+	}
+
+	if s.filter.IsEditing() {
+		lines = append(lines, s.help.ShortHelpView([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "apply filter"),
+			),
+			key.NewBinding(
+				key.WithKeys("esc"),
+				key.WithHelp("esc", "cancel"),
+			),
+		}))
+
+		return strings.Join(lines, "\n")
+	}
+
 	lines = append(lines, s.help.ShortHelpView([]key.Binding{
 		key.NewBinding(
 			key.WithKeys("up"),
@@ -216,6 +399,18 @@ func (s *model) View() string {
 			key.WithKeys("d", "delete"),
 			key.WithHelp("d", "remove entry"),
 		),
+		key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "export"),
+		),
+		key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "import"),
+		),
 		key.NewBinding(
 			key.WithKeys("q", "esc"),
 			key.WithHelp("q", "quit"),
@@ -224,3 +419,93 @@ func (s *model) View() string {
 
 	return strings.Join(lines, "\n")
 }
+
+// export writes the games matching the filter as json archive and as csv into the working directory
+func (s *model) export() {
+	games, err := s.listAll()
+	if err != nil {
+		s.failure = fmt.Errorf("export failed: %w", err)
+		return
+	}
+
+	matches, err := s.ds.ListMatchStats()
+	if err != nil {
+		s.failure = fmt.Errorf("export failed: %w", err)
+		return
+	}
+
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		s.failure = fmt.Errorf("export failed: %w", err)
+		return
+	}
+
+	base := "darts-counter-export-" + time.Now().Format("20060102-150405")
+
+	err = writeFile(base+".json", func(w io.Writer) error {
+		return datastore.WriteArchive(w, datastore.NewArchive(games, matches, profiles, time.Now()))
+	})
+	if err != nil {
+		s.failure = fmt.Errorf("export failed: %w", err)
+		return
+	}
+
+	err = writeFile(base+".csv", func(w io.Writer) error {
+		return datastore.WriteMovesCSV(w, games, s.names)
+	})
+	if err != nil {
+		s.failure = fmt.Errorf("export failed: %w", err)
+		return
+	}
+
+	s.log.Info("exported games", "entries", len(games), "path", base)
+
+	s.message = fmt.Sprintf("exported %d games to %s.json and %s.csv", len(games), base, base)
+}
+
+func (s *model) importArchive() tea.Cmd {
+	a, err := readArchive(s.importBar.Query())
+	if err != nil {
+		s.failure = fmt.Errorf("import failed: %w", err)
+		return nil
+	}
+
+	result, err := s.ds.ImportArchive(a)
+	if err != nil {
+		s.failure = fmt.Errorf("import failed: %w", err)
+		return nil
+	}
+
+	s.log.Info("imported games", "entries", result.Games, "skipped", result.Skipped)
+
+	cmd := s.Init()
+
+	s.message = fmt.Sprintf("imported %d games (%d already known) and %d new players", result.Games, result.Skipped, result.Players)
+
+	return cmd
+}
+
+func readArchive(path string) (*datastore.Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return datastore.ReadArchive(f)
+}
+
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = write(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/Gerrit91/darts-counter/pkg/checkout"
@@ -28,6 +29,8 @@ type (
 		cursor    int
 		err       error
 		showInput string
+		// editStartScore is set when the input edits the start score of a player instead of the name
+		editStartScore bool
 
 		textInput textinput.Model
 		help      help.Model
@@ -44,6 +47,11 @@ var (
 	gameTypeSettings           settingsChoice = "game-type"
 	checkinSettings            settingsChoice = "check-in"
 	checkoutSettings           settingsChoice = "check-out"
+	setsSettings               settingsChoice = "sets"
+	skipAheadSettings          settingsChoice = "skip-ahead"
+	legsSettings               settingsChoice = "legs"
+	startScoreSettings         settingsChoice = "start-score"
+	perDartInputSettings       settingsChoice = "per-dart-input"
 	playerSettings             settingsChoice = "player"
 	saveSettings               settingsChoice = "save"
 	saveGameToStats            settingsChoice = "save-game-to-stats"
@@ -72,6 +80,8 @@ func (g *model) Init() tea.Cmd {
 					{Name: "Player 2"},
 				},
 				SaveGameToStats: true,
+				Sets:            1,
+				Legs:            1,
 			}
 		} else {
 			g.err = err
@@ -93,6 +103,9 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			config.GameType501,
 			config.GameType701,
 			config.GameType1001,
+			config.GameTypeCustom,
+			config.GameTypeCricket,
+			config.GameTypeAroundTheClock,
 		}
 		gameTypeToggle = func(left bool) {
 			idx := slices.IndexFunc(gameTypes, func(gt config.GameType) bool {
@@ -112,19 +125,53 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				g.settings.Type = gameTypes[(idx+1)%len(gameTypes)]
 			}
-		}
-		checkoutToggle = func() {
-			if g.settings.Checkout == checkout.CheckoutTypeStraightOut {
-				g.settings.Checkout = checkout.CheckoutTypeDoubleOut
-			} else {
-				g.settings.Checkout = checkout.CheckoutTypeStraightOut
+
+			switch g.settings.Type {
+			case config.GameTypeCricket:
+				g.settings.Sets = 1
+				g.settings.Legs = 1
+			case config.GameTypeCustom:
+				if g.settings.StartScore <= 0 {
+					g.settings.StartScore = 170
+				}
 			}
+
+			g.updateChoices()
+		}
+		checkoutTypes = []checkout.CheckoutType{
+			checkout.CheckoutTypeStraightOut,
+			checkout.CheckoutTypeDoubleOut,
+			checkout.CheckoutTypeMasterOut,
+		}
+		checkoutToggle = func(left bool) {
+			g.settings.Checkout = cycle(checkoutTypes, g.settings.Checkout, left)
 		}
-		checkinToggle = func() {
-			if g.settings.Checkin == checkout.CheckinTypeStraightIn {
-				g.settings.Checkin = checkout.CheckinTypeDoubleIn
+		checkinTypes = []checkout.CheckinType{
+			checkout.CheckinTypeStraightIn,
+			checkout.CheckinTypeDoubleIn,
+			checkout.CheckinTypeMasterIn,
+		}
+		checkinToggle = func(left bool) {
+			g.settings.Checkin = cycle(checkinTypes, g.settings.Checkin, left)
+		}
+		bestOfToggle = func(bestOf *int, left bool) {
+			// best of is always an odd number, so a winner is determined
+			const maxBestOf = 13
+
+			if *bestOf < 1 {
+				*bestOf = 1
+			}
+
+			if left {
+				*bestOf -= 2
+				if *bestOf < 1 {
+					*bestOf = maxBestOf
+				}
 			} else {
-				g.settings.Checkin = checkout.CheckinTypeStraightIn
+				*bestOf += 2
+				if *bestOf > maxBestOf {
+					*bestOf = 1
+				}
 			}
 		}
 		rotatePlayers = func() {
@@ -143,6 +190,8 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "esc":
 				g.showInput = ""
+				g.editStartScore = false
+				g.textInput.Reset()
 				return g, nil
 			case "enter":
 				switch g.choices[g.cursor] {
@@ -156,11 +205,38 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						g.textInput.Reset()
 						return g, nil
 					}
+				case startScoreSettings:
+					score, err := parseStartScore(g.textInput.Value(), g.settings.Checkout)
+					if err != nil {
+						g.err = err
+						return g, nil
+					}
+					if score == 0 {
+						g.err = fmt.Errorf("custom games require a start score")
+						return g, nil
+					}
+
+					g.showInput = ""
+					g.settings.StartScore = score
+					g.textInput.Reset()
+					return g, nil
 				default:
 					switch choice := g.choices[g.cursor].(type) {
 					case playerChoice:
+						if g.editStartScore {
+							score, err := parseStartScore(g.textInput.Value(), g.settings.Checkout)
+							if err != nil {
+								g.err = err
+								return g, nil
+							}
+
+							g.settings.Players[choice.idx].StartScore = score
+						} else {
+							g.settings.Players[choice.idx].Name = g.textInput.Value()
+						}
+
 						g.showInput = ""
-						g.settings.Players[choice.idx].Name = g.textInput.Value()
+						g.editStartScore = false
 						g.updateChoices()
 						g.textInput.Reset()
 						return g, nil
@@ -192,11 +268,23 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case gameTypeSettings:
 				gameTypeToggle(false)
 			case checkinSettings:
-				checkinToggle()
+				checkinToggle(false)
 			case checkoutSettings:
-				checkoutToggle()
+				checkoutToggle(false)
+			case setsSettings:
+				bestOfToggle(&g.settings.Sets, false)
+			case legsSettings:
+				bestOfToggle(&g.settings.Legs, false)
 			case playerSettings:
 				rotatePlayers()
+			case startScoreSettings:
+				g.showInput = "Enter Start Score:"
+				g.textInput.SetValue(strconv.Itoa(g.settings.StartScore))
+				return g, nil
+			case skipAheadSettings:
+				g.settings.SkipAhead = !g.settings.SkipAhead
+			case perDartInputSettings:
+				g.settings.PerDartInput = !g.settings.PerDartInput
 			case saveGameToStats:
 				g.settings.SaveGameToStats = !g.settings.SaveGameToStats
 			default:
@@ -213,9 +301,17 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case gameTypeSettings:
 				gameTypeToggle(false)
 			case checkinSettings:
-				checkinToggle()
+				checkinToggle(false)
 			case checkoutSettings:
-				checkoutToggle()
+				checkoutToggle(false)
+			case setsSettings:
+				bestOfToggle(&g.settings.Sets, false)
+			case legsSettings:
+				bestOfToggle(&g.settings.Legs, false)
+			case skipAheadSettings:
+				g.settings.SkipAhead = !g.settings.SkipAhead
+			case perDartInputSettings:
+				g.settings.PerDartInput = !g.settings.PerDartInput
 			case saveGameToStats:
 				g.settings.SaveGameToStats = !g.settings.SaveGameToStats
 			}
@@ -224,9 +320,17 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case gameTypeSettings:
 				gameTypeToggle(true)
 			case checkinSettings:
-				checkinToggle()
+				checkinToggle(true)
 			case checkoutSettings:
-				checkoutToggle()
+				checkoutToggle(true)
+			case setsSettings:
+				bestOfToggle(&g.settings.Sets, true)
+			case legsSettings:
+				bestOfToggle(&g.settings.Legs, true)
+			case skipAheadSettings:
+				g.settings.SkipAhead = !g.settings.SkipAhead
+			case perDartInputSettings:
+				g.settings.PerDartInput = !g.settings.PerDartInput
 			case saveGameToStats:
 				g.settings.SaveGameToStats = !g.settings.SaveGameToStats
 			}
@@ -240,6 +344,20 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if g.cursor < 0 {
 				g.cursor = len(g.choices) - 1
 			}
+		case "s":
+			switch choice := g.choices[g.cursor].(type) {
+			case playerChoice:
+				if !isX01(g.settings.Type) {
+					return g, nil
+				}
+
+				g.showInput = fmt.Sprintf("Enter Start Score of %s (empty for the game's start score):", choice.name)
+				g.editStartScore = true
+				if score := g.settings.Players[choice.idx].StartScore; score > 0 {
+					g.textInput.SetValue(strconv.Itoa(score))
+				}
+				return g, nil
+			}
 		case "+":
 			switch g.choices[g.cursor] {
 			case playerSettings:
@@ -342,6 +460,46 @@ func (g *model) View() string {
 					key.WithHelp("←/→", "toggle"),
 				),
 			},
+			setsSettings: {
+				key.NewBinding(
+					key.WithKeys("up", "down"),
+					key.WithHelp("↑/↓", "up/down"),
+				),
+				key.NewBinding(
+					key.WithKeys("enter", "left", "right"),
+					key.WithHelp("←/→", "toggle"),
+				),
+			},
+			legsSettings: {
+				key.NewBinding(
+					key.WithKeys("up", "down"),
+					key.WithHelp("↑/↓", "up/down"),
+				),
+				key.NewBinding(
+					key.WithKeys("enter", "left", "right"),
+					key.WithHelp("←/→", "toggle"),
+				),
+			},
+			skipAheadSettings: {
+				key.NewBinding(
+					key.WithKeys("up", "down"),
+					key.WithHelp("↑/↓", "up/down"),
+				),
+				key.NewBinding(
+					key.WithKeys("enter", "left", "right"),
+					key.WithHelp("←/→", "toggle"),
+				),
+			},
+			perDartInputSettings: {
+				key.NewBinding(
+					key.WithKeys("up", "down"),
+					key.WithHelp("↑/↓", "up/down"),
+				),
+				key.NewBinding(
+					key.WithKeys("enter", "left", "right"),
+					key.WithHelp("←/→", "toggle"),
+				),
+			},
 			saveGameToStats: {
 				key.NewBinding(
 					key.WithKeys("up", "down"),
@@ -352,6 +510,16 @@ func (g *model) View() string {
 					key.WithHelp("←/→", "toggle"),
 				),
 			},
+			startScoreSettings: {
+				key.NewBinding(
+					key.WithKeys("up", "down"),
+					key.WithHelp("↑/↓", "up/down"),
+				),
+				key.NewBinding(
+					key.WithKeys("enter"),
+					key.WithHelp("enter", "edit"),
+				),
+			},
 			saveSettings: {
 				key.NewBinding(
 					key.WithKeys("enter"),
@@ -397,10 +565,20 @@ func (g *model) View() string {
 			switch choice := g.choices[i]; choice {
 			case gameTypeSettings:
 				lines = append(lines, selection+style.Render(common.Fill("Type:", 13)+string(g.settings.Type)))
+			case startScoreSettings:
+				lines = append(lines, selection+style.Render(common.Fill("Start Score:", 12), strconv.Itoa(g.settings.StartScore)))
 			case checkinSettings:
 				lines = append(lines, selection+style.Render(common.Fill("Check-In:", 12), string(g.settings.Checkin)))
 			case checkoutSettings:
 				lines = append(lines, selection+style.Render(common.Fill("Check-Out:", 12), string(g.settings.Checkout)))
+			case setsSettings:
+				lines = append(lines, selection+style.Render(common.Fill("Sets:", 12), fmt.Sprintf("best of %d", max(g.settings.Sets, 1))))
+			case legsSettings:
+				lines = append(lines, selection+style.Render(common.Fill("Legs:", 12), fmt.Sprintf("best of %d", max(g.settings.Legs, 1))))
+			case skipAheadSettings:
+				lines = append(lines, selection+style.Render("Doubles/Triples Skip Ahead:", common.FormatBool(g.settings.SkipAhead)))
+			case perDartInputSettings:
+				lines = append(lines, selection+style.Render("Enter Every Dart Individually:", common.FormatBool(g.settings.PerDartInput)))
 			case playerSettings:
 				lines = append(lines, selection+style.Render("Players:"))
 			case saveSettings:
@@ -431,8 +609,18 @@ func (g *model) View() string {
 								key.WithHelp("page up/down", "toggle"),
 							),
 						}
+						if isX01(g.settings.Type) {
+							helpKeyBinding = append(helpKeyBinding, key.NewBinding(
+								key.WithKeys("s"),
+								key.WithHelp("s", "start score"),
+							))
+						}
+					}
+					name := choice.name
+					if score := g.settings.Players[choice.idx].StartScore; score > 0 && isX01(g.settings.Type) {
+						name = fmt.Sprintf("%s (starts at %d)", name, score)
 					}
-					lines = append(lines, style.Render(fmt.Sprintf("   %s%d. %s", selection, choice.idx+1, choice.name)))
+					lines = append(lines, style.Render(fmt.Sprintf("   %s%d. %s", selection, choice.idx+1, name)))
 				}
 			}
 		}
@@ -454,11 +642,33 @@ func (g *model) View() string {
 func (g *model) updateChoices() {
 	g.choices = []any{
 		gameTypeSettings,
-		checkinSettings,
-		checkoutSettings,
-		playerSettings,
 	}
 
+	switch g.settings.Type {
+	case config.GameTypeCricket:
+		// cricket has no check-in, check-out and is played in a single leg
+	case config.GameTypeAroundTheClock:
+		g.choices = append(g.choices,
+			skipAheadSettings,
+			setsSettings,
+			legsSettings,
+		)
+	default:
+		if g.settings.Type == config.GameTypeCustom {
+			g.choices = append(g.choices, startScoreSettings)
+		}
+
+		g.choices = append(g.choices,
+			checkinSettings,
+			checkoutSettings,
+			perDartInputSettings,
+			setsSettings,
+			legsSettings,
+		)
+	}
+
+	g.choices = append(g.choices, playerSettings)
+
 	for i, p := range g.settings.Players {
 		g.choices = append(g.choices, playerChoice{
 			name: p.Name,
@@ -472,3 +682,41 @@ func (g *model) updateChoices() {
 		leaveSettingsWithoutSaving,
 	)
 }
+
+// cycle returns the value next to the current one, wrapping around at the ends
+func cycle[T comparable](values []T, current T, left bool) T {
+	idx := slices.Index(values, current)
+	if idx == -1 {
+		return values[0]
+	}
+
+	if left {
+		return values[(idx+len(values)-1)%len(values)]
+	}
+
+	return values[(idx+1)%len(values)]
+}
+
+// isX01 returns whether players count down from a start score in the given game type
+func isX01(gt config.GameType) bool {
+	return gt != config.GameTypeCricket && gt != config.GameTypeAroundTheClock
+}
+
+// parseStartScore parses a start score input, an empty input results in zero
+func parseStartScore(input string, out checkout.CheckoutType) (int, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return 0, nil
+	}
+
+	score, err := strconv.Atoi(input)
+	if err != nil || score <= 0 {
+		return 0, fmt.Errorf("start score must be a number greater than zero")
+	}
+
+	if score == 1 && out.LeavesOneBust() {
+		return 0, fmt.Errorf("a start score of 1 cannot be finished with %s", out)
+	}
+
+	return score, nil
+}
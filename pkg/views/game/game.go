@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -12,7 +13,10 @@ import (
 	"github.com/Gerrit91/darts-counter/pkg/checkout"
 	"github.com/Gerrit91/darts-counter/pkg/config"
 	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/match"
 	"github.com/Gerrit91/darts-counter/pkg/player"
+	"github.com/Gerrit91/darts-counter/pkg/remote"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
 	"github.com/Gerrit91/darts-counter/pkg/views/common"
 	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
 
@@ -29,7 +33,15 @@ type (
 	model struct {
 		log      *slog.Logger
 		ds       datastore.Datastore
+		board    *scoreboard.Broadcaster
 		settings *datastore.GameSettings
+		names    datastore.PlayerNames
+
+		match      *match.Match
+		matchID    string
+		matchStart time.Time
+		results    []datastore.LegResult
+		count      int
 
 		id            string
 		players       player.Players
@@ -43,71 +55,210 @@ type (
 		msg           string
 		finished      bool
 
+		// in per-dart mode, the darts of the current turn are entered one by one
+		perDart bool
+		darts   []*checkout.Score
+
+		// a total that finishes the player is only applied after the darts of the checkout were entered
+		finishTotal int
+
 		textInput   textinput.Model
 		help        help.Model
 		gameDetails *gamedetails.Model
 	}
-
-	undoMoveMsg struct{}
 )
 
-func UndoMove() tea.Msg {
-	return undoMoveMsg{}
-}
-
-func New(log *slog.Logger, ds datastore.Datastore, show *gamedetails.Model) (*model, error) {
+func New(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster, show *gamedetails.Model) (*model, error) {
 	settings, err := ds.GetGameSettings()
 	if err != nil {
 		// TODO: if not found, redirect to settings view
 		return nil, fmt.Errorf("unable to retrieve game settings: %w", err)
 	}
 
-	uuid, err := uuid.NewV7()
+	g, err := newModel(log, ds, board, show, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.match.IsSingleLeg() {
+		matchID, err := uuid.NewV7()
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate uuid: %w", err)
+		}
+
+		g.matchID = matchID.String()
+	}
+
+	err = g.startLeg()
 	if err != nil {
-		return nil, fmt.Errorf("unable to generate uuid: %w", err)
+		return nil, err
+	}
+
+	g.checkpoint()
+
+	return g, nil
+}
+
+// Resume continues the game of the last checkpoint by replaying its moves
+func Resume(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster, show *gamedetails.Model) (*model, error) {
+	cp, err := ds.GetCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve checkpoint: %w", err)
+	}
+
+	g, err := newModel(log, ds, board, show, &cp.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	g.matchID = cp.MatchID
+	g.matchStart = cp.MatchStart
+
+	for _, result := range cp.Results {
+		_, err := g.match.FinishLeg(result.Winner)
+		if err != nil {
+			return nil, fmt.Errorf("unable to replay leg result: %w", err)
+		}
+
+		g.results = append(g.results, result)
+	}
+
+	err = g.startLeg()
+	if err != nil {
+		return nil, err
+	}
+
+	g.id = cp.GameID
+	g.start = cp.Start
+
+	for _, m := range cp.Moves {
+		var scores []*checkout.Score
+
+		for _, field := range m.Score.Fields {
+			score, err := checkout.ParseScore(field)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse field of previous move: %w", err)
+			}
+
+			scores = append(scores, score)
+		}
+
+		total := sumDarts(scores)
+		if len(scores) == 0 {
+			total = m.Score.Total
+		}
+
+		g.tick(scores, total, m.Darts)
+		if g.err != nil {
+			return nil, fmt.Errorf("unable to replay previous move: %w", g.err)
+		}
 	}
 
+	if g.iter.GetRound() != cp.Round || g.CurrentPlayerID() != cp.Player {
+		return nil, fmt.Errorf("replayed game does not match the checkpoint (round %d, player %q)", cp.Round, cp.Player)
+	}
+
+	// the durations of the replayed moves are taken from the checkpoint
+	g.moves = cp.Moves
+	g.msg = "Resumed game from " + cp.Updated.Format(time.DateTime)
+	g.publish()
+
+	log.Info("resumed game from checkpoint", "id", g.id, "moves", len(g.moves))
+
+	return g, nil
+}
+
+func newModel(log *slog.Logger, ds datastore.Datastore, board *scoreboard.Broadcaster, show *gamedetails.Model, settings *datastore.GameSettings) (*model, error) {
 	count := 0
 
 	switch gt := settings.Type; gt {
 	case config.GameType101, config.GameType301, config.GameType501, config.GameType701, config.GameType1001:
 		count, _ = strconv.Atoi(string(gt))
+	case config.GameTypeCustom:
+		count = settings.StartScore
+	case config.GameTypeAroundTheClock:
+		// the player counts the targets that are left
 	default:
 		return nil, fmt.Errorf("unknown game: %s", gt)
 	}
 
-	var players player.Players
+	var (
+		ids   []string
+		names = datastore.PlayerNames{}
+	)
 	for _, p := range settings.Players {
-		players = append(players, player.New(p.Name, settings.Checkout, settings.Checkin, count))
+		ids = append(ids, p.ID)
+		names[p.ID] = p.Name
+	}
+
+	g := &model{
+		log:         log,
+		ds:          ds,
+		board:       board,
+		settings:    settings,
+		names:       names,
+		match:       match.New(ids, settings.Sets, settings.Legs),
+		matchStart:  time.Now(),
+		count:       count,
+		perDart:     settings.PerDartInput && settings.Type != config.GameTypeAroundTheClock,
+		textInput:   common.NewTextInput(),
+		help:        common.NewHelp(),
+		gameDetails: show,
+	}
+
+	return g, nil
+}
+
+// startLeg resets the game state for the next leg of the match
+func (g *model) startLeg() error {
+	uuid, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("unable to generate uuid: %w", err)
+	}
+
+	var players player.Players
+	for _, id := range g.match.StartingOrder() {
+		if g.settings.Type == config.GameTypeAroundTheClock {
+			players = append(players, player.NewAroundTheClock(g.names.Of(id), g.settings.SkipAhead).WithID(id))
+			continue
+		}
+
+		players = append(players, player.New(g.names.Of(id), g.settings.Checkout, g.settings.Checkin, g.startScoreOf(id)).WithID(id))
 	}
 
 	playerIterator := players.Iterator()
 	currentPlayer, err := playerIterator.Next()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	now := time.Now()
 
-	return &model{
-		log:           log,
-		ds:            ds,
-		settings:      settings,
-		id:            uuid.String(),
-		players:       players,
-		currentPlayer: currentPlayer,
-		start:         now,
-		startMove:     now,
-		iter:          playerIterator,
-		rank:          1,
-		moves:         nil,
-		err:           nil,
-		msg:           "",
-		finished:      false,
-		textInput:     common.NewTextInput(),
-		help:          common.NewHelp(),
-		gameDetails:   show,
-	}, nil
+	g.id = uuid.String()
+	g.players = players
+	g.currentPlayer = currentPlayer
+	g.start = now
+	g.startMove = now
+	g.iter = playerIterator
+	g.rank = 1
+	g.moves = nil
+	g.darts = nil
+	g.err = nil
+	g.msg = ""
+	g.finished = false
+
+	return nil
+}
+
+// startScoreOf returns the start score of a player, which differs from the game's start score in handicap games
+func (g *model) startScoreOf(id string) int {
+	for _, p := range g.settings.Players {
+		if p.ID == id && p.StartScore > 0 {
+			return p.StartScore
+		}
+	}
+
+	return g.count
 }
 
 func (g *model) Init() tea.Cmd {
@@ -124,39 +275,25 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		g.textInput, cmd = g.textInput.Update(msg)
 		return g, cmd
-	case undoMoveMsg:
-		if len(g.moves) == 0 {
-			g.err = fmt.Errorf("cannot go back any further, no previous moves")
-			return g, nil
-		}
-
-		lastIdx := len(g.moves) - 1
-		lastMove := g.moves[lastIdx]
-
-		lastPlayer, err := g.iter.SetBackTo(lastMove.Player)
-		if err != nil {
-			g.err = err
-			return g, nil
-		}
-
-		if lastPlayer.HasFinished() {
-			g.rank--
-		}
-		if g.currentPlayer.HasFinished() {
-			g.rank--
-		}
+	case common.UndoMoveMsg:
+		g.undo()
+		return g, nil
+	case remote.InputMsg:
+		g.err = nil
+		g.msg = ""
 
-		err = lastPlayer.Edit(lastPlayer.GetRemaining() + lastMove.Score.Total)
-		if err != nil {
-			g.err = err
-			return g, nil
+		switch {
+		case msg.Action == remote.ActionUndo:
+			g.undo()
+		case g.finished:
+			g.err = fmt.Errorf("the game is finished, continue in the terminal")
+		case msg.Action == remote.ActionSkip:
+			g.skip()
+		default:
+			g.submit(msg.Input)
 		}
 
-		g.finished = false
-		g.currentPlayer.SetRank(0)
-		lastPlayer.SetRank(0)
-		g.moves = g.moves[:lastIdx]
-		g.currentPlayer = lastPlayer
+		msg.Reply(g.err, g.msg, g.prompt())
 
 		return g, nil
 	case tea.KeyMsg:
@@ -167,12 +304,24 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "esc":
 			return g, common.SwitchViewTo(common.CloseGameDialogView)
 		case "v":
-			g.gameDetails.SetGameStats(*g.gameStats())
+			g.gameDetails.SetGameStats(*g.GameStats())
 			return g, common.SwitchViewTo(common.GameDetailsView)
 		case "u":
 			return g, common.SwitchViewTo(common.UndoMoveView)
 		case "s":
-			g.tick(nil, 0)
+			g.skip()
+			return g, nil
+		case "tab":
+			if g.settings.Type == config.GameTypeAroundTheClock {
+				return g, nil
+			}
+
+			if len(g.darts) > 0 || g.finishTotal > 0 {
+				g.err = fmt.Errorf("finish the current turn before switching the input mode")
+				return g, nil
+			}
+
+			g.perDart = !g.perDart
 			return g, nil
 		case "enter":
 			defer func() {
@@ -180,20 +329,10 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}()
 
 			if g.finished {
-				if err := g.persist(); err != nil {
-					g.log.Error("error persisting finished game to database", "error", err)
-				}
-
-				return g, common.SwitchViewTo(common.MainMenuView)
+				return g, g.finishLeg()
 			}
 
-			scores, total, err := g.parseScore(g.textInput.Value())
-			if err != nil {
-				g.err = err
-				return g, nil
-			}
-
-			g.tick(scores, total)
+			g.submit(g.textInput.Value())
 
 			return g, nil
 		default:
@@ -207,6 +346,122 @@ func (g *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return g, nil
 }
 
+// undo takes back the last dart of the current turn or the last move
+func (g *model) undo() {
+	if g.finishTotal > 0 {
+		g.finishTotal = 0
+		return
+	}
+
+	if len(g.darts) > 0 {
+		g.darts = g.darts[:len(g.darts)-1]
+		g.publish()
+		return
+	}
+
+	if len(g.moves) == 0 {
+		g.err = fmt.Errorf("cannot go back any further, no previous moves")
+		return
+	}
+
+	lastIdx := len(g.moves) - 1
+	lastMove := g.moves[lastIdx]
+
+	lastPlayer, err := g.iter.SetBackTo(lastMove.Player)
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	if lastPlayer.HasFinished() {
+		g.rank--
+	}
+	if g.currentPlayer != nil && g.currentPlayer.HasFinished() {
+		g.rank--
+	}
+
+	err = lastPlayer.Edit(lastPlayer.GetRemaining() + lastMove.Score.Total)
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	if g.finished {
+		for _, p := range g.players {
+			if !p.HasFinished() {
+				p.SetRank(0)
+			}
+		}
+	}
+
+	g.finished = false
+	if g.currentPlayer != nil {
+		g.currentPlayer.SetRank(0)
+	}
+	lastPlayer.SetRank(0)
+	g.moves = g.moves[:lastIdx]
+	g.currentPlayer = lastPlayer
+	g.checkpoint()
+}
+
+// skip ends the turn of the current player, in per-dart mode the darts that were already thrown still count
+func (g *model) skip() {
+	darts := g.darts
+	g.darts = nil
+	g.finishTotal = 0
+	g.tick(darts, sumDarts(darts), 0)
+	g.checkpoint()
+}
+
+// submit applies an entered input of the current turn
+func (g *model) submit(input string) {
+	if g.finishTotal > 0 {
+		darts, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil || darts < 1 || darts > 3 {
+			g.err = fmt.Errorf("the checkout takes one to three darts, please enter again")
+			return
+		}
+
+		if len(checkout.For(g.finishTotal, checkout.NewCheckoutTypeOption(g.settings.Checkout), checkout.NewMaxThrowsOption(darts))) == 0 {
+			g.err = fmt.Errorf("%d cannot be checked out with %d darts, please enter again", g.finishTotal, darts)
+			return
+		}
+
+		total := g.finishTotal
+		g.finishTotal = 0
+		g.tick(nil, total, darts)
+		g.checkpoint()
+
+		return
+	}
+
+	if g.perDart {
+		g.throwDart(input)
+		g.checkpoint()
+		return
+	}
+
+	scores, total, err := common.ParseTurn(input)
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	if len(scores) == 0 && total == g.currentPlayer.GetRemaining() && g.settings.Type != config.GameTypeAroundTheClock {
+		if len(checkout.For(total, checkout.NewCheckoutTypeOption(g.settings.Checkout))) == 0 {
+			g.err = fmt.Errorf("not possible to finish with %d points, please enter again", total)
+			return
+		}
+
+		// without fields, the darts of the checkout are unknown
+		g.finishTotal = total
+		return
+	}
+
+	g.tick(scores, total, 0)
+	g.checkpoint()
+}
+
 func (g *model) View() string {
 	var (
 		lines        []string
@@ -218,12 +473,21 @@ func (g *model) View() string {
 		if len(p.GetName()) > longestName {
 			longestName = len(p.GetName())
 		}
-		if r := strconv.Itoa(p.GetRemaining()); len(r) > longestScore {
+		if r := strconv.Itoa(g.remaining(p)); len(r) > longestScore {
 			longestScore = len(r)
 		}
 	}
 
-	lines = append(lines, common.Headline(fmt.Sprintf("Game %s: Round %d", g.settings.Type, g.iter.GetRound())))
+	gameName := string(g.settings.Type)
+	if g.settings.Type == config.GameTypeCustom {
+		gameName = strconv.Itoa(g.count)
+	}
+
+	if g.match.IsSingleLeg() {
+		lines = append(lines, common.Headline(fmt.Sprintf("Game %s: Round %d", gameName, g.iter.GetRound())))
+	} else {
+		lines = append(lines, common.Headline(fmt.Sprintf("Game %s: Set %d, Leg %d (%s), Round %d", gameName, g.match.GetSet(), g.match.GetLeg(), g.match.Score(), g.iter.GetRound())))
+	}
 
 	lines = append(lines, "")
 
@@ -252,15 +516,31 @@ func (g *model) View() string {
 			slices.Reverse(moves)
 
 			for _, m := range moves {
-				if m.Player == p.GetName() {
-					infos = append(infos, common.StylePink.Render(fmt.Sprintf("(—%d)", m.Score.Total)))
+				if m.Player == p.GetID() {
+					if g.settings.Type == config.GameTypeAroundTheClock {
+						infos = append(infos, common.StylePink.Render(fmt.Sprintf("(+%d)", m.Score.Total)))
+					} else if m.Bust {
+						infos = append(infos, common.StyleError.Render("(bust)"))
+					} else {
+						infos = append(infos, common.StylePink.Render(fmt.Sprintf("(—%d)", m.Score.Total)))
+					}
 					break
 				}
 			}
 		}
 
-		if p.GetRemaining() > 0 {
-			variants := checkout.For(p.GetRemaining(), checkout.NewCalcLimitOption(3), checkout.NewCheckoutTypeOption(g.settings.Checkout))
+		if g.settings.Type == config.GameTypeAroundTheClock {
+			if target := p.GetTarget(); target > 0 {
+				infos = append(infos, common.StyleInactive.Render("target: "+checkout.NewScore(target).String()))
+			}
+		} else if remaining := g.remaining(p); remaining > 0 {
+			dartsLeft := 3
+			if p == g.currentPlayer && len(g.darts) > 0 {
+				infos = append(infos, common.StylePink.Render(fmt.Sprintf("[%s]", joinDarts(g.darts))))
+				dartsLeft -= len(g.darts)
+			}
+
+			variants := g.checkoutsFor(remaining, dartsLeft)
 			switch len(variants) {
 			case 0:
 			case 1, 2:
@@ -273,7 +553,7 @@ func (g *model) View() string {
 		lines = append(lines,
 			common.StylePink.Render(common.Fill(currentPlayerArrow, 3))+
 				playerStyle.Render(common.Fill(playerName, longestName+8))+
-				scoreStyle.Render(common.Fill(strconv.Itoa(p.GetRemaining()), longestScore+3))+
+				scoreStyle.Render(common.Fill(strconv.Itoa(g.remaining(p)), longestScore+3))+
 				strings.Join(infos, " "),
 		)
 	}
@@ -288,11 +568,17 @@ func (g *model) View() string {
 	}
 
 	if g.finished {
-		lines = append(lines, "Game finished.")
+		finishedHelp := "return to main menu"
+		if g.match.IsSingleLeg() {
+			lines = append(lines, "Game finished.")
+		} else {
+			lines = append(lines, "Leg finished.")
+			finishedHelp = "next leg"
+		}
 		lines = append(lines, g.help.ShortHelpView([]key.Binding{
 			key.NewBinding(
 				key.WithKeys("enter"),
-				key.WithHelp("enter", "return to main menu"),
+				key.WithHelp("enter", finishedHelp),
 			),
 			key.NewBinding(
 				key.WithKeys("u"),
@@ -308,16 +594,26 @@ func (g *model) View() string {
 			),
 		}))
 	} else {
-		lines = append(lines, "Enter score:")
+		undoHelp := "undo last move"
+
+		switch {
+		case g.finishTotal > 0:
+			undoHelp = "undo checkout"
+		case g.perDart && len(g.darts) > 0:
+			undoHelp = "undo last dart"
+		}
+
+		lines = append(lines, g.prompt())
 		lines = append(lines, g.textInput.View())
-		lines = append(lines, g.help.ShortHelpView([]key.Binding{
+
+		bindings := []key.Binding{
 			key.NewBinding(
 				key.WithKeys("s"),
 				key.WithHelp("s", "skip player"),
 			),
 			key.NewBinding(
 				key.WithKeys("u"),
-				key.WithHelp("u", "undo last move"),
+				key.WithHelp("u", undoHelp),
 			),
 			key.NewBinding(
 				key.WithKeys("v"),
@@ -327,29 +623,138 @@ func (g *model) View() string {
 				key.WithKeys("q", "esc"),
 				key.WithHelp("q", "quit"),
 			),
-		}))
+		}
+		if g.settings.Type != config.GameTypeAroundTheClock {
+			inputHelp := "enter per dart"
+			if g.perDart {
+				inputHelp = "enter per turn"
+			}
+
+			bindings = append(bindings, key.NewBinding(
+				key.WithKeys("tab"),
+				key.WithHelp("tab", inputHelp),
+			))
+		}
+		lines = append(lines, g.help.ShortHelpView(bindings))
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-func (g *model) tick(scores []*checkout.Score, total int) {
-	if g.finished {
+// prompt returns the description of the expected input
+func (g *model) prompt() string {
+	switch {
+	case g.finished:
+		return ""
+	case g.finishTotal > 0:
+		return fmt.Sprintf("Enter darts needed to check out %d (1-3):", g.finishTotal)
+	case g.settings.Type == config.GameTypeAroundTheClock:
+		return "Enter fields (M for a miss):"
+	case g.perDart:
+		return fmt.Sprintf("Enter dart %d of 3 (M for a miss):", len(g.darts)+1)
+	default:
+		return "Enter score:"
+	}
+}
+
+// checkoutsFor returns the checkout hints for a remaining score
+func (g *model) checkoutsFor(remaining, dartsLeft int) checkout.Checkouts {
+	return checkout.For(remaining, checkout.NewCalcLimitOption(3), checkout.NewCheckoutTypeOption(g.settings.Checkout), checkout.NewMaxThrowsOption(dartsLeft))
+}
+
+// remaining returns the remaining score of a player including the darts already thrown in the current turn
+func (g *model) remaining(p *player.Player) int {
+	if p != g.currentPlayer {
+		return p.GetRemaining()
+	}
+
+	return p.GetRemaining() - p.Counted(g.darts)
+}
+
+// throwDart adds a single dart to the current turn, the turn is completed after the third dart,
+// on checkout or as soon as the player busts
+func (g *model) throwDart(input string) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		g.err = fmt.Errorf("no points entered")
+		return
+	}
+
+	score, err := checkout.ParseScore(input)
+	if err != nil {
+		g.err = fmt.Errorf("unable to parse input (%q), please enter again", err.Error())
 		return
 	}
 
+	darts := append(slices.Clone(g.darts), score)
+
 	p := g.currentPlayer
+	if p.CheckBust(darts) == nil && len(darts) < 3 && p.GetRemaining()-p.Counted(darts) > 0 {
+		g.darts = darts
+		return
+	}
 
-	err := p.Move(scores, total)
-	if err != nil {
-		if errors.Is(err, player.ErrInvalidInput) {
+	g.darts = nil
+	g.tick(darts, sumDarts(darts), len(darts))
+}
+
+func sumDarts(darts []*checkout.Score) int {
+	total := 0
+	for _, d := range darts {
+		total += d.Value()
+	}
+	return total
+}
+
+func joinDarts(darts []*checkout.Score) string {
+	var fields []string
+	for _, d := range darts {
+		fields = append(fields, d.String())
+	}
+	return strings.Join(fields, " ")
+}
+
+// tick applies the turn of the current player, the thrown darts are inferred from the fields if not given
+func (g *model) tick(scores []*checkout.Score, total, thrown int) {
+	if g.finished {
+		return
+	}
+
+	var (
+		p     = g.currentPlayer
+		darts int
+		bust  bool
+		err   error
+	)
+
+	switch g.settings.Type {
+	case config.GameTypeAroundTheClock:
+		total, darts, err = p.MoveAroundTheClock(scores)
+		if err != nil {
 			g.err = err
 			return
 		}
+	default:
+		total, err = p.Move(scores, total)
+		if err != nil {
+			if !errors.Is(err, player.ErrBust) {
+				g.err = err
+				return
+			}
 
-		// on other errors, game can continue
-		g.msg = err.Error()
-		err = nil
+			// a busted turn is recorded with zero points
+			bust = true
+			g.msg = err.Error()
+		}
+
+		darts = thrown
+		if darts == 0 {
+			darts = 3
+			if len(scores) > 0 && (bust || p.HasFinished()) {
+				// the turn ends with the dart that busted or checked out
+				darts = len(scores)
+			}
+		}
 	}
 
 	if p.HasFinished() {
@@ -370,12 +775,22 @@ func (g *model) tick(scores []*checkout.Score, total int) {
 
 	g.moves = append(g.moves, datastore.Move{
 		Round:     g.iter.GetRound(),
-		Player:    p.GetName(),
+		Player:    p.GetID(),
 		Score:     statsScore,
 		Remaining: p.GetRemaining(),
 		Duration:  since.String(),
+		Darts:     darts,
+		Bust:      bust,
 	})
 
+	if p.HasFinished() && !g.match.IsSingleLeg() {
+		// in a match, a leg is decided as soon as the first player checks out
+		g.rankRemaining()
+		g.currentPlayer = nil
+		g.finished = true
+		return
+	}
+
 	p, err = g.iter.Next()
 	g.currentPlayer = p
 
@@ -394,44 +809,106 @@ func (g *model) tick(scores []*checkout.Score, total int) {
 	}
 }
 
-func (g *model) parseScore(input string) ([]*checkout.Score, int, error) {
-	var (
-		// allow both comma and space separated
-		segments = strings.Fields(strings.Join(strings.Split(strings.TrimSpace(input), ","), " "))
-		total    int
-		scores   []*checkout.Score
-	)
+// rankRemaining ranks the players who have not finished by their remaining score
+func (g *model) rankRemaining() {
+	var remaining player.Players
+	for _, p := range g.players {
+		if !p.HasFinished() {
+			remaining = append(remaining, p)
+		}
+	}
 
-	switch len(segments) {
-	case 0:
-		return nil, 0, fmt.Errorf("no points entered")
-	case 1, 2, 3:
-		var (
-			sum   int
-			score *checkout.Score
-			err   error
-		)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return remaining[i].GetRemaining() < remaining[j].GetRemaining()
+	})
 
-		for _, segment := range segments {
-			score, err = checkout.ParseScore(segment)
-			if err != nil {
-				break
-			}
+	rank := g.rank
+	for _, p := range remaining {
+		p.SetRank(rank)
+		rank++
+	}
+}
 
-			sum += score.Value()
-			scores = append(scores, score)
-		}
+// finishLeg persists the finished leg and either starts the next leg or returns to the main menu
+func (g *model) finishLeg() tea.Cmd {
+	if err := g.persist(); err != nil {
+		g.log.Error("error persisting finished game to database", "error", err)
+	}
 
-		if err != nil {
-			return nil, 0, fmt.Errorf("unable to parse input (%q), please enter again", err.Error())
+	if g.match.IsSingleLeg() {
+		g.deleteCheckpoint()
+		return common.SwitchViewTo(common.MainMenuView)
+	}
+
+	var winner string
+	for _, p := range g.players {
+		if p.GetRank() == 1 {
+			winner = p.GetID()
 		}
+	}
 
-		total = sum
-	default:
-		return nil, 0, fmt.Errorf("no more than three throws are allowed, please enter again")
+	result := datastore.LegResult{
+		GameID: g.id,
+		Set:    g.match.GetSet(),
+		Leg:    g.match.GetLeg(),
+		Winner: winner,
+	}
+
+	matchFinished, err := g.match.FinishLeg(winner)
+	if err != nil {
+		g.err = err
+		return nil
+	}
+
+	g.results = append(g.results, result)
+
+	if err := g.persistMatch(); err != nil {
+		g.log.Error("error persisting match to database", "error", err)
+	}
+
+	if matchFinished {
+		g.deleteCheckpoint()
+		return common.SwitchViewTo(common.MainMenuView)
 	}
 
-	return scores, total, nil
+	if err := g.startLeg(); err != nil {
+		g.err = err
+		return nil
+	}
+
+	g.checkpoint()
+
+	g.msg = fmt.Sprintf("%s won the leg!", g.names.Of(winner))
+
+	return nil
+}
+
+func (g *model) persistMatch() error {
+	if !g.settings.SaveGameToStats {
+		g.log.Info("not saving match to database because disabled in game settings")
+		return nil
+	}
+
+	err := g.ds.CreateMatchStats(&datastore.MatchStats{
+		ID:       g.matchID,
+		GameType: g.settings.Type,
+		Sets:     g.match.GetSets(),
+		Legs:     g.match.GetLegs(),
+		Players:  g.match.GetPlayers(),
+		Winner:   g.match.GetWinner(),
+		SetsWon:  g.match.SetsWon(),
+		LegsWon:  g.match.TotalLegsWon(),
+		Results:  g.results,
+		Start:    g.matchStart,
+		End:      time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	g.log.Info("saved match stats to database", "id", g.matchID)
+
+	return nil
 }
 
 func (g *model) persist() error {
@@ -440,7 +917,7 @@ func (g *model) persist() error {
 		return nil
 	}
 
-	err := g.ds.CreateGameStats(g.gameStats())
+	err := g.ds.CreateGameStats(g.GameStats())
 	if err != nil {
 		return err
 	}
@@ -450,16 +927,135 @@ func (g *model) persist() error {
 	return nil
 }
 
-func (g *model) gameStats() *datastore.GameStats {
+// checkpoint stores the state of the running game, so that it can be resumed after a crash or quit,
+// and publishes it to the scoreboard
+func (g *model) checkpoint() {
+	g.publish()
+
+	ranks := datastore.Ranks{}
+	for _, p := range g.players {
+		if p.GetRank() > 0 {
+			ranks[p.GetRank()] = p.GetID()
+		}
+	}
+
+	err := g.ds.UpdateCheckpoint(&datastore.Checkpoint{
+		Settings:   *g.settings,
+		GameID:     g.id,
+		Start:      g.start,
+		Moves:      g.moves,
+		Round:      g.iter.GetRound(),
+		Player:     g.CurrentPlayerID(),
+		Ranks:      ranks,
+		MatchID:    g.matchID,
+		MatchStart: g.matchStart,
+		Results:    g.results,
+		Updated:    time.Now(),
+	})
+	if err != nil {
+		g.log.Error("error storing checkpoint of running game", "error", err)
+	}
+}
+
+// publish sends the state of the running game to the viewers of the scoreboard
+func (g *model) publish() {
+	gameName := string(g.settings.Type)
+	if g.settings.Type == config.GameTypeCustom {
+		gameName = strconv.Itoa(g.count)
+	}
+
+	state := &scoreboard.State{
+		Game:     gameName,
+		Round:    g.iter.GetRound(),
+		Message:  g.msg,
+		Finished: g.finished,
+		Updated:  time.Now(),
+	}
+
+	if !g.match.IsSingleLeg() {
+		state.Set = g.match.GetSet()
+		state.Leg = g.match.GetLeg()
+		state.MatchScore = g.match.Score()
+	}
+
+	for _, p := range g.players {
+		ps := scoreboard.PlayerState{
+			ID:        p.GetID(),
+			Name:      p.GetName(),
+			Remaining: g.remaining(p),
+			Rank:      p.GetRank(),
+			Current:   g.currentPlayer != nil && p == g.currentPlayer,
+		}
+
+		if g.settings.Type == config.GameTypeAroundTheClock {
+			if target := p.GetTarget(); target > 0 {
+				ps.Target = checkout.NewScore(target).String()
+			}
+		} else if ps.Remaining > 0 {
+			dartsLeft := 3
+			if ps.Current {
+				for _, d := range g.darts {
+					ps.Darts = append(ps.Darts, d.String())
+				}
+				dartsLeft -= len(g.darts)
+			}
+
+			for _, cs := range g.checkoutsFor(ps.Remaining, dartsLeft) {
+				ps.Checkouts = append(ps.Checkouts, cs.String())
+			}
+		}
+
+		state.Players = append(state.Players, ps)
+	}
+
+	if len(g.moves) > 0 {
+		last := g.moves[len(g.moves)-1]
+		state.LastMove = &scoreboard.LastMoveState{
+			Player: g.names.Of(last.Player),
+			Score:  last.Score.Total,
+			Fields: last.Score.Fields,
+			Bust:   last.Bust,
+		}
+	}
+
+	g.board.Publish(state)
+}
+
+func (g *model) deleteCheckpoint() {
+	err := g.ds.DeleteCheckpoint()
+	if err != nil {
+		g.log.Error("error deleting checkpoint of finished game", "error", err)
+	}
+}
+
+// CurrentPlayerID returns the ID of the player whose turn it is, it is empty when the leg is finished
+func (g *model) CurrentPlayerID() string {
+	if g.currentPlayer == nil {
+		return ""
+	}
+
+	return g.currentPlayer.GetID()
+}
+
+// GameStats returns the statistics of the running leg
+func (g *model) GameStats() *datastore.GameStats {
 	var (
-		playerNames []string
+		playerIDs   []string
 		ranks       = map[int]string{}
+		startScores map[string]int
 	)
 	for _, p := range g.players {
 		if g.finished {
-			ranks[p.GetRank()] = p.GetName()
+			ranks[p.GetRank()] = p.GetID()
+		}
+		playerIDs = append(playerIDs, p.GetID())
+	}
+
+	if g.settings.Type != config.GameTypeAroundTheClock {
+		startScores = map[string]int{}
+		for _, id := range playerIDs {
+			startScores[id] = g.startScoreOf(id)
 		}
-		playerNames = append(playerNames, p.GetName())
 	}
 
 	return &datastore.GameStats{
@@ -467,11 +1063,16 @@ func (g *model) gameStats() *datastore.GameStats {
 		GameType: g.settings.Type,
 		Checkin:  string(g.settings.Checkin),
 		Checkout: string(g.settings.Checkout),
-		Players:  playerNames,
+		Players:  playerIDs,
 		Rounds:   g.iter.GetRound(),
 		Ranks:    ranks,
 		Start:    g.start,
 		End:      time.Now(),
 		Moves:    g.moves,
+		MatchID:  g.matchID,
+		Set:      g.match.GetSet(),
+		Leg:      g.match.GetLeg(),
+
+		StartScores: startScores,
 	}
 }
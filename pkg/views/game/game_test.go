@@ -0,0 +1,186 @@
+package game
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gerrit91/darts-counter/pkg/checkout"
+	"github.com/Gerrit91/darts-counter/pkg/config"
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
+	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGame(t *testing.T, settings *datastore.GameSettings) (*model, func() (*model, error)) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ds, err := datastore.New(log, &config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(ds.Close)
+
+	require.NoError(t, ds.UpdateGameSettings(settings))
+
+	board := scoreboard.NewBroadcaster(log)
+
+	g, err := New(log, ds, board, gamedetails.New(log, ds))
+	require.NoError(t, err)
+
+	return g, func() (*model, error) {
+		return Resume(log, ds, board, gamedetails.New(log, ds))
+	}
+}
+
+// play submits the given inputs one after another, the input + switches between per-dart and turn input
+func play(t *testing.T, g *model, inputs ...string) {
+	for _, input := range inputs {
+		if input == "+" {
+			g.perDart = !g.perDart
+			continue
+		}
+
+		g.submit(input)
+		require.NoError(t, g.err, "input %q", input)
+	}
+}
+
+func assertResumed(t *testing.T, want, got *model) {
+	assert.Equal(t, want.id, got.id)
+	assert.Equal(t, want.CurrentPlayerID(), got.CurrentPlayerID())
+	assert.Equal(t, want.iter.GetRound(), got.iter.GetRound())
+	assert.Equal(t, want.rank, got.rank)
+	assert.Equal(t, want.finished, got.finished)
+	assert.Equal(t, want.match.GetSet(), got.match.GetSet())
+	assert.Equal(t, want.match.GetLeg(), got.match.GetLeg())
+	assert.Equal(t, want.match.Score(), got.match.Score())
+	assert.Equal(t, want.results, got.results)
+	assert.Equal(t, withoutDurations(want.moves), withoutDurations(got.moves))
+
+	require.Len(t, got.players, len(want.players))
+	for i, p := range want.players {
+		assert.Equal(t, p.GetID(), got.players[i].GetID())
+		assert.Equal(t, p.GetRemaining(), got.players[i].GetRemaining(), "remaining of %s", p.GetName())
+		assert.Equal(t, p.GetRank(), got.players[i].GetRank(), "rank of %s", p.GetName())
+		assert.Equal(t, p.HasFinished(), got.players[i].HasFinished(), "finished of %s", p.GetName())
+	}
+}
+
+// withoutDurations removes the durations of moves, which differ between games that are played at different times
+func withoutDurations(moves []datastore.Move) []datastore.Move {
+	var res []datastore.Move
+	for _, m := range moves {
+		m.Duration = ""
+		res = append(res, m)
+	}
+	return res
+}
+
+func TestResume(t *testing.T) {
+	players := []datastore.Player{{Name: "Alice"}, {Name: "Bob"}, {Name: "Carol"}}
+
+	t.Run("single leg", func(t *testing.T) {
+		g, resume := newTestGame(t, &datastore.GameSettings{
+			Type:     config.GameType301,
+			Checkout: checkout.CheckoutTypeDoubleOut,
+			Checkin:  checkout.CheckinTypeStraightIn,
+			Players:  players,
+		})
+
+		play(t, g,
+			// round 1
+			"T20 T20 T20",
+			"100",
+			"+", "T20", "T20", "T20", "+",
+			// round 2, alice checks out with a total, carol busts in per-dart mode
+			"121", "3",
+			"60",
+			"+", "T20", "T20", "+",
+			// round 3
+			"T20",
+		)
+
+		require.Equal(t, "Carol", g.names.Of(g.CurrentPlayerID()))
+		require.Equal(t, 1, g.players[0].GetRank())
+		require.True(t, g.moves[5].Bust)
+
+		r, err := resume()
+		require.NoError(t, err)
+		assertResumed(t, g, r)
+
+		// the resumed game goes on like the original one
+		play(t, g, "T20 T11 D14")
+		play(t, r, "T20 T11 D14")
+		assertResumed(t, g, r)
+
+		assert.True(t, r.finished)
+		assert.Equal(t, 2, r.players[2].GetRank())
+		assert.Equal(t, 3, r.players[1].GetRank())
+	})
+
+	t.Run("match", func(t *testing.T) {
+		g, resume := newTestGame(t, &datastore.GameSettings{
+			Type:     config.GameType101,
+			Checkout: checkout.CheckoutTypeDoubleOut,
+			Checkin:  checkout.CheckinTypeStraightIn,
+			Players:  players[:2],
+			Sets:     1,
+			Legs:     3,
+		})
+
+		play(t, g, "T20 T7 D10")
+		require.True(t, g.finished)
+		require.Nil(t, g.finishLeg())
+
+		play(t, g, "60", "41", "+", "T15", "+")
+		require.Equal(t, 2, g.match.GetLeg())
+
+		r, err := resume()
+		require.NoError(t, err)
+		assertResumed(t, g, r)
+	})
+}
+
+func TestSubmit_CheckoutAsTotal(t *testing.T) {
+	newGame := func(t *testing.T, start int) *model {
+		g, _ := newTestGame(t, &datastore.GameSettings{
+			Type:       config.GameTypeCustom,
+			StartScore: start,
+			Checkout:   checkout.CheckoutTypeDoubleOut,
+			Checkin:    checkout.CheckinTypeStraightIn,
+			Players:    []datastore.Player{{Name: "Alice"}},
+		})
+		return g
+	}
+
+	t.Run("impossible finish is rejected before the darts are asked", func(t *testing.T) {
+		g := newGame(t, 169)
+
+		g.submit("169")
+		require.Error(t, g.err)
+		assert.Zero(t, g.finishTotal)
+		assert.Empty(t, g.moves)
+	})
+
+	t.Run("darts must suffice for the checkout", func(t *testing.T) {
+		g := newGame(t, 100)
+
+		g.submit("100")
+		require.NoError(t, g.err)
+		assert.Equal(t, 100, g.finishTotal)
+
+		g.submit("1")
+		require.EqualError(t, g.err, "100 cannot be checked out with 1 darts, please enter again")
+		assert.Equal(t, 100, g.finishTotal)
+
+		g.err = nil
+		g.submit("2")
+		require.NoError(t, g.err)
+		require.Len(t, g.moves, 1)
+		assert.Equal(t, 2, g.moves[0].Darts)
+		assert.True(t, g.finished)
+	})
+}
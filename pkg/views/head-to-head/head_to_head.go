@@ -0,0 +1,191 @@
+package headtohead
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/views/common"
+	playerdetails "github.com/Gerrit91/darts-counter/pkg/views/player-details"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Model struct {
+	log *slog.Logger
+	ds  datastore.Datastore
+
+	h     *datastore.HeadToHead
+	names datastore.PlayerNames
+	err   error
+
+	viewport viewport.Model
+	help     help.Model
+
+	backTo tea.Cmd
+}
+
+func New(log *slog.Logger, ds datastore.Datastore) *Model {
+	return &Model{
+		log:      log,
+		ds:       ds,
+		viewport: viewport.New(0, 20),
+		backTo:   common.SwitchViewTo(common.PlayerListView),
+		help:     common.NewHelp(),
+	}
+}
+
+func (s *Model) Init() tea.Cmd {
+	s.viewport.GotoTop()
+	return tea.WindowSize()
+}
+
+func (s *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			return s, s.backTo
+		case "g":
+			s.viewport.GotoTop()
+		case "G":
+			s.viewport.GotoBottom()
+		}
+	case tea.WindowSizeMsg:
+		headerHeight := 2
+		footerHeight := 1
+		verticalMarginHeight := headerHeight + footerHeight
+		s.viewport.Width = msg.Width
+		s.viewport.Height = msg.Height - verticalMarginHeight
+	}
+
+	var cmd tea.Cmd
+	s.viewport, cmd = s.viewport.Update(msg)
+
+	return s, cmd
+}
+
+func (s *Model) View() string {
+	var lines []string
+
+	if s.err != nil || s.h == nil {
+		lines = append(lines, common.Headline("Head to Head"))
+		if s.err != nil {
+			lines = append(lines, common.StyleError.Render(s.err.Error()))
+		}
+		lines = append(lines, s.help.ShortHelpView([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("q", "esc"),
+				key.WithHelp("q", "quit"),
+			),
+		}))
+		return strings.Join(lines, "\n")
+	}
+
+	var (
+		viewportLines []string
+		a, b          = s.h.Players[0], s.h.Players[1]
+	)
+
+	compareTable := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
+		switch {
+		case row == -1:
+			return common.StylePink
+		case col == 0:
+			return common.StyleInactive
+		}
+		return common.StyleActive
+	})
+	compareTable.Headers("", a.Name, b.Name)
+	compareTable.Row("Wins:", strconv.Itoa(s.h.Wins[0]), strconv.Itoa(s.h.Wins[1]))
+	compareTable.Row("3-Dart ⌀:", strconv.FormatFloat(a.ThreeDartAverage, 'f', 2, 64), strconv.FormatFloat(b.ThreeDartAverage, 'f', 2, 64))
+	compareTable.Row("First 9 ⌀:", strconv.FormatFloat(a.FirstNineAverage, 'f', 2, 64), strconv.FormatFloat(b.FirstNineAverage, 'f', 2, 64))
+	compareTable.Row("Checkout:", strconv.FormatFloat(a.CheckoutPercentage, 'f', 1, 64)+"%", strconv.FormatFloat(b.CheckoutPercentage, 'f', 1, 64)+"%")
+	compareTable.Row("Highest Score:", strconv.Itoa(a.HighestScore.Total), strconv.Itoa(b.HighestScore.Total))
+	compareTable.Row("Highest Checkout:", strconv.Itoa(a.HighestCheckout), strconv.Itoa(b.HighestCheckout))
+	compareTable.Row("Busts:", strconv.Itoa(a.Busts), strconv.Itoa(b.Busts))
+	if a.TotalMarks > 0 || b.TotalMarks > 0 {
+		compareTable.Row("Cricket Marks per Round:", strconv.FormatFloat(a.MarksPerRound, 'f', 2, 64), strconv.FormatFloat(b.MarksPerRound, 'f', 2, 64))
+	}
+
+	viewportLines = append(viewportLines, fmt.Sprintf("Shared Games: %s", common.StyleActive.Render(strconv.Itoa(len(s.h.Games)))), "")
+	viewportLines = append(viewportLines, compareTable.Render())
+
+	viewportLines = append(viewportLines, "", "Field Counts:")
+	viewportLines = append(viewportLines, lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		a.Name+"\n"+playerdetails.FieldsTable(a.FieldsCount).Render(),
+		"    ",
+		b.Name+"\n"+playerdetails.FieldsTable(b.FieldsCount).Render(),
+	))
+
+	gamesTable := common.NewTable().Headers("Date", "Game", "Winner", "Players")
+	for _, g := range s.h.Games {
+		gamesTable.Row(
+			g.Start.Format(time.DateTime),
+			g.GameName(),
+			s.names.Of(g.Ranks[1]),
+			strings.Join(s.names.All(g.Players), ", "),
+		)
+	}
+
+	viewportLines = append(viewportLines, "", "Games:")
+	viewportLines = append(viewportLines, gamesTable.Render())
+
+	if s.viewport.Height > 0 { // otherwise it crashes
+		s.viewport.SetContent(strings.Join(viewportLines, "\n"))
+	}
+
+	lines = append(lines, common.Headline(fmt.Sprintf("%s vs. %s", a.Name, b.Name)))
+	lines = append(lines, s.viewport.View())
+
+	lines = append(lines, s.help.ShortHelpView([]key.Binding{
+		key.NewBinding(
+			key.WithKeys("up", "down"),
+			key.WithHelp("↑/↓", "up/down"),
+		),
+		key.NewBinding(
+			key.WithKeys("g", "G"),
+			key.WithHelp("g/G", "top/bottom"),
+		),
+		key.NewBinding(
+			key.WithKeys("q"),
+			key.WithHelp("q", "quit"),
+		),
+	})+common.StyleHelp.Render(fmt.Sprintf(" (%3.f%%)", s.viewport.ScrollPercent()*100)))
+
+	return strings.Join(lines, "\n")
+}
+
+// SetPlayers compares the two players with the given IDs
+func (s *Model) SetPlayers(a, b string) {
+	s.h = nil
+	s.err = nil
+
+	gameStats, err := s.ds.ListGameStats(datastore.PlayerFilter(a), datastore.PlayerFilter(b))
+	if err != nil {
+		s.err = err
+		return
+	}
+
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		s.err = err
+		return
+	}
+
+	s.names = datastore.ToPlayerNames(profiles)
+
+	s.h, err = datastore.ToHeadToHead(gameStats, s.names, a, b)
+	if err != nil {
+		s.err = err
+		return
+	}
+}
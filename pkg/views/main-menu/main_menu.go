@@ -7,14 +7,19 @@ import (
 
 	"github.com/Gerrit91/darts-counter/pkg/config"
 	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/remote"
+	"github.com/Gerrit91/darts-counter/pkg/scoreboard"
 	"github.com/Gerrit91/darts-counter/pkg/views/common"
 	"github.com/Gerrit91/darts-counter/pkg/views/confirm-dialog"
+	cricketgame "github.com/Gerrit91/darts-counter/pkg/views/cricket-game"
 	"github.com/Gerrit91/darts-counter/pkg/views/game"
 	gamedetails "github.com/Gerrit91/darts-counter/pkg/views/game-details"
 	gamelist "github.com/Gerrit91/darts-counter/pkg/views/game-list"
 	gamesettings "github.com/Gerrit91/darts-counter/pkg/views/game-settings"
+	headtohead "github.com/Gerrit91/darts-counter/pkg/views/head-to-head"
 	playerdetails "github.com/Gerrit91/darts-counter/pkg/views/player-details"
 	playerlist "github.com/Gerrit91/darts-counter/pkg/views/player-list"
+	playermanagement "github.com/Gerrit91/darts-counter/pkg/views/player-management"
 
 	"github.com/charmbracelet/bubbles/cursor"
 	tea "github.com/charmbracelet/bubbletea"
@@ -27,6 +32,11 @@ type (
 		log *slog.Logger
 		ds  datastore.Datastore
 
+		// board receives the state of running games for the scoreboard
+		board *scoreboard.Broadcaster
+		// pairingCode has to be entered on devices for the remote input, empty if it is disabled
+		pairingCode string
+
 		cursor  int
 		choices []mainMenuChoice
 		err     error
@@ -40,30 +50,27 @@ type (
 )
 
 const (
-	menuNewGame      mainMenuChoice = "Start New Game"
-	menuGameSettings mainMenuChoice = "Game Settings"
-	menuShowPlayers  mainMenuChoice = "Show Players"
-	menuShowGames    mainMenuChoice = "Show Games"
-	menuQuit         mainMenuChoice = "Exit"
+	menuResumeGame    mainMenuChoice = "Resume Game"
+	menuNewGame       mainMenuChoice = "Start New Game"
+	menuGameSettings  mainMenuChoice = "Game Settings"
+	menuShowPlayers   mainMenuChoice = "Show Players"
+	menuShowGames     mainMenuChoice = "Show Games"
+	menuManagePlayers mainMenuChoice = "Manage Players"
+	menuQuit          mainMenuChoice = "Exit"
 )
 
-func New(log *slog.Logger, c *config.Config, ds datastore.Datastore) *model {
+func New(log *slog.Logger, c *config.Config, ds datastore.Datastore, board *scoreboard.Broadcaster) *model {
 	m := &model{
-		cfg: c,
-		log: log,
-		ds:  ds,
-		choices: []mainMenuChoice{
-			menuNewGame,
-			menuGameSettings,
-			menuShowPlayers,
-			menuShowGames,
-			menuQuit,
-		},
+		cfg:              c,
+		log:              log,
+		ds:               ds,
+		board:            board,
 		currentView:      common.MainMenuView,
 		gameDetailsModel: gamedetails.New(log, ds),
 	}
 
 	playerDetailsModel := playerdetails.New(log, ds)
+	headToHeadModel := headtohead.New(log, ds)
 
 	m.views = map[common.View]tea.Model{
 		common.MainMenuView:     m,
@@ -77,14 +84,14 @@ func New(log *slog.Logger, c *config.Config, ds datastore.Datastore) *model {
 		),
 		common.CloseGameDialogView: confirm.New(
 			log,
-			"Are you sure you want to quit a running game?\nAll progress will be lost.",
+			"Are you sure you want to quit a running game?\nIt can be resumed from the main menu.",
 			common.SwitchViewTo(common.MainMenuView),
 			common.SwitchViewTo(common.GameView),
 		),
 		common.UndoMoveView: confirm.New(
 			log,
 			"Are you sure you want to undo the last move?",
-			tea.Sequence(common.SwitchViewTo(common.GameView), game.UndoMove),
+			tea.Sequence(common.SwitchViewTo(common.GameView), common.UndoMove),
 			common.SwitchViewTo(common.GameView),
 		),
 		common.GameListView:    gamelist.New(log, ds, m.gameDetailsModel),
@@ -93,13 +100,54 @@ func New(log *slog.Logger, c *config.Config, ds datastore.Datastore) *model {
 			log,
 			ds,
 			playerDetailsModel,
+			headToHeadModel,
+		),
+		common.PlayerDetailsView:    playerDetailsModel,
+		common.HeadToHeadView:       headToHeadModel,
+		common.PlayerManagementView: playermanagement.New(log, ds),
+		common.MergePlayersView: confirm.New(
+			log,
+			"Are you sure you want to merge these players?\nAll games of the first player are assigned to the second one.",
+			tea.Sequence(common.SwitchViewTo(common.PlayerManagementView), playermanagement.MergePlayers),
+			common.SwitchViewTo(common.PlayerManagementView),
 		),
-		common.PlayerDetailsView: playerDetailsModel,
 	}
 
+	m.updateChoices()
+
 	return m
 }
 
+// updateChoices offers to resume a game if there is a checkpoint of a running game
+func (m *model) updateChoices() {
+	m.choices = nil
+
+	_, err := m.ds.GetCheckpoint()
+	if err == nil {
+		m.choices = append(m.choices, menuResumeGame)
+	} else if !errors.Is(err, datastore.ErrNotFound) {
+		m.log.Error("unable to retrieve checkpoint", "error", err)
+	}
+
+	m.choices = append(m.choices,
+		menuNewGame,
+		menuGameSettings,
+		menuShowPlayers,
+		menuShowGames,
+		menuManagePlayers,
+		menuQuit,
+	)
+
+	if m.cursor >= len(m.choices) {
+		m.cursor = 0
+	}
+}
+
+// SetPairingCode shows the code that is required to enter scores on remote devices
+func (m *model) SetPairingCode(code string) {
+	m.pairingCode = code
+}
+
 func (m *model) Init() tea.Cmd {
 	return nil
 }
@@ -111,6 +159,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.currentView = msg.To()
 
 		if m.currentView == common.MainMenuView {
+			m.updateChoices()
 			return m, nil
 		}
 
@@ -130,6 +179,13 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.QuitMsg:
 		m.log.Info("received quit msg, exiting")
 		return m, tea.Quit
+	case remote.InputMsg:
+		m.log.Info("received remote input message", "action", msg.Action, "input", msg.Input)
+
+		if m.currentView != common.GameView {
+			msg.Reply(errors.New("no running game is shown in the terminal"), "", "")
+			return m, nil
+		}
 	case cursor.BlinkMsg, tea.MouseMsg:
 		// do not log this
 	default:
@@ -158,8 +214,18 @@ func (m *model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "enter":
 			switch m.choices[m.cursor] {
+			case menuResumeGame:
+				g, err := m.resumeGame()
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+
+				m.views[common.GameView] = g
+
+				return m, common.SwitchViewTo(common.GameView)
 			case menuNewGame:
-				g, err := game.New(m.log, m.ds, m.gameDetailsModel)
+				g, err := m.newGame()
 				if err != nil {
 					if errors.Is(err, datastore.ErrNotFound) {
 						return m, common.SwitchViewTo(common.GameSettingsView)
@@ -179,6 +245,8 @@ func (m *model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, common.SwitchViewTo(common.GameListView)
 			case menuShowPlayers:
 				return m, common.SwitchViewTo(common.PlayerListView)
+			case menuManagePlayers:
+				return m, common.SwitchViewTo(common.PlayerManagementView)
 			default:
 
 			}
@@ -202,6 +270,32 @@ func (m *model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m *model) newGame() (tea.Model, error) {
+	settings, err := m.ds.GetGameSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Type == config.GameTypeCricket {
+		return cricketgame.New(m.log, m.ds, m.board, m.gameDetailsModel)
+	}
+
+	return game.New(m.log, m.ds, m.board, m.gameDetailsModel)
+}
+
+func (m *model) resumeGame() (tea.Model, error) {
+	cp, err := m.ds.GetCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	if cp.Settings.Type == config.GameTypeCricket {
+		return cricketgame.Resume(m.log, m.ds, m.board, m.gameDetailsModel)
+	}
+
+	return game.Resume(m.log, m.ds, m.board, m.gameDetailsModel)
+}
+
 func (m *model) View() string {
 	if m.currentView == common.MainMenuView {
 		return m.view()
@@ -231,6 +325,10 @@ func (m *model) view() string {
 		lines = append(lines, selection+common.StyleInactive.Render(string(m.choices[i])))
 	}
 
+	if m.pairingCode != "" {
+		lines = append(lines, "", common.StyleInactive.Render("Pairing code for the remote input at /remote: ")+common.StyleActive.Render(m.pairingCode))
+	}
+
 	if m.err != nil {
 		lines = append(lines, "", common.StyleError.Render(m.err.Error()))
 	}
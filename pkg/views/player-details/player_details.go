@@ -17,13 +17,15 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
 )
 
 type Model struct {
 	log *slog.Logger
 	ds  datastore.Datastore
 
-	ps *datastore.PlayerStats
+	ps   *datastore.PlayerStats
+	form []datastore.FormPoint
 
 	viewport viewport.Model
 	help     help.Model
@@ -99,74 +101,71 @@ func (s *Model) View() string {
 		)
 	}
 
-	fieldsTable := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
-		switch {
-		case col == 0:
-			return common.StylePink
-		case row == -1:
-			return common.StylePink
+	infoTable := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
+		if col == 0 {
+			return common.StyleInactive
 		}
-		return common.StyleInactive
+		return common.StyleActive
 	})
+	infoTable.Row("Games Played:", strconv.Itoa(ps.GamesPlayed))
+	infoTable.Row("Total Moves:", strconv.Itoa(ps.TotalMoves))
+	infoTable.Row("Total Move Time:", ps.TotalDuration.Truncate(time.Second).String())
+	infoTable.Row("⌀-Duration per Move:", ps.AverageDuration.Truncate(time.Millisecond).String())
 
-	headers := []string{" "}
-	for _, score := range checkout.Singles() {
-		headers = append(headers, common.Fill(score.String(), 2))
-	}
-	fieldsTable.Headers(headers...)
+	viewportLines = append(viewportLines, infoTable.Render())
 
-	countToCol := map[int]string{}
-	for _, m := range []checkout.Multiplier{checkout.None, checkout.Double, checkout.Triple} {
-		for _, score := range checkout.Singles() {
-			if m == checkout.Triple && score.Value() == checkout.BullsEye {
-				continue
-			}
-			count := score.WithMultiplier(m).String()
-			countToCol[ps.FieldsCount[count]] = ""
+	var ratings, averages []float64
+	for _, f := range s.form {
+		ratings = append(ratings, f.Rating)
+		if f.ThreeDartAverage > 0 {
+			averages = append(averages, f.ThreeDartAverage)
 		}
 	}
 
-	common.DistributeColors(string(common.ColorInactive), string(common.ColorGreen), countToCol)
-
-	for _, m := range []checkout.Multiplier{checkout.None, checkout.Double, checkout.Triple} {
-		row := []string{string(m)}
-		for _, score := range checkout.Singles() {
-			if m == checkout.Triple && score.Value() == checkout.BullsEye {
-				row = append(row, "")
-				continue
-			}
-			count := ps.FieldsCount[score.WithMultiplier(m).String()]
-			row = append(row, lipgloss.NewStyle().Foreground(lipgloss.Color(countToCol[count])).Render(strconv.Itoa(count)))
-		}
-		fieldsTable.Row(row...)
-	}
+	sparklineWidth := max(10, s.viewport.Width-30)
 
-	infoTable := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
+	formTable := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
 		if col == 0 {
 			return common.StyleInactive
 		}
-		return common.StyleActive
+		return common.StylePink
 	})
-	infoTable.Row("Games Played:", strconv.Itoa(ps.GamesPlayed))
-	infoTable.Row("Total Moves:", strconv.Itoa(ps.TotalMoves))
-	infoTable.Row("Total Move Time:", ps.TotalDuration.Truncate(time.Second).String())
-	infoTable.Row("⌀-Duration per Move:", ps.AverageDuration.Truncate(time.Millisecond).String())
+	formTable.Row("Rating:", common.Sparkline(ratings, sparklineWidth), common.StyleActive.Render(strconv.FormatFloat(ps.Rating, 'f', 0, 64)))
+	if len(averages) > 0 {
+		formTable.Row("3-Dart ⌀ (rolling):", common.Sparkline(averages, sparklineWidth), common.StyleActive.Render(strconv.FormatFloat(averages[len(averages)-1], 'f', 1, 64)))
+	}
 
-	viewportLines = append(viewportLines, infoTable.Render())
+	viewportLines = append(viewportLines, "", "Form:")
+	viewportLines = append(viewportLines, formTable.Render())
 
 	viewportLines = append(viewportLines, "", fmt.Sprintf("Ranks (⌀ %s):", strconv.FormatFloat(ps.AverageRank, 'f', 3, 64)))
 	viewportLines = append(viewportLines, ranksTable.Render())
 
 	viewportLines = append(viewportLines, "", "Field Counts:")
-	viewportLines = append(viewportLines, fieldsTable.Render())
+	viewportLines = append(viewportLines, FieldsTable(ps.FieldsCount).Render())
 	viewportLines = append(viewportLines, "⌀-Score: "+common.StyleActive.Render(strconv.FormatFloat(ps.AverageScore, 'f', 1, 64)))
 	viewportLines = append(viewportLines, "Highest Score: "+common.StyleActive.Render(fmt.Sprintf("%d (%s)", ps.HighestScore.Total, strings.Join(ps.HighestScore.Fields, " → "))))
+	viewportLines = append(viewportLines, "Busts: "+common.StyleActive.Render(strconv.Itoa(ps.Busts)))
+	viewportLines = append(viewportLines, "3-Dart ⌀: "+common.StyleActive.Render(strconv.FormatFloat(ps.ThreeDartAverage, 'f', 2, 64)))
+	viewportLines = append(viewportLines, "First 9 ⌀: "+common.StyleActive.Render(strconv.FormatFloat(ps.FirstNineAverage, 'f', 2, 64)))
+	viewportLines = append(viewportLines, "Checkouts: "+common.StyleActive.Render(fmt.Sprintf("%s%% (%d/%d darts at a finish)", strconv.FormatFloat(ps.CheckoutPercentage, 'f', 1, 64), ps.Checkouts, ps.CheckoutDarts)))
+	viewportLines = append(viewportLines, "Highest Checkout: "+common.StyleActive.Render(strconv.Itoa(ps.HighestCheckout)))
+	if ps.BestLeg > 0 {
+		viewportLines = append(viewportLines, "Best Leg: "+common.StyleActive.Render(fmt.Sprintf("%d darts", ps.BestLeg)))
+	}
+	viewportLines = append(viewportLines, "100+ / 140+ / 180: "+common.StyleActive.Render(fmt.Sprintf("%d / %d / %d", ps.Scores100, ps.Scores140, ps.Scores180)))
+	if ps.TotalMarks > 0 {
+		viewportLines = append(viewportLines, "Cricket Marks per Round: "+common.StyleActive.Render(strconv.FormatFloat(ps.MarksPerRound, 'f', 2, 64)))
+	}
+	if ps.AroundTheClockRuns > 0 {
+		viewportLines = append(viewportLines, "Around the Clock: "+common.StyleActive.Render(fmt.Sprintf("%d runs, best %d darts, ⌀ %s darts", ps.AroundTheClockRuns, ps.AroundTheClockBestRun, strconv.FormatFloat(ps.AroundTheClockAvgDarts, 'f', 1, 64))))
+	}
 
 	if s.viewport.Height > 0 { // otherwise it crashes
 		s.viewport.SetContent(strings.Join(viewportLines, "\n"))
 	}
 
-	lines = append(lines, common.Headline(ps.ID))
+	lines = append(lines, common.Headline(ps.Name))
 	lines = append(lines, s.viewport.View())
 
 	lines = append(lines, s.help.ShortHelpView([]key.Binding{
@@ -192,10 +191,68 @@ func (s *Model) View() string {
 	return strings.Join(lines, "\n")
 }
 
+// FieldsTable returns a heatmap of how often each field was hit
+func FieldsTable(fieldsCount map[string]int) *table.Table {
+	fieldsTable := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
+		switch {
+		case col == 0:
+			return common.StylePink
+		case row == -1:
+			return common.StylePink
+		}
+		return common.StyleInactive
+	})
+
+	headers := []string{" "}
+	for _, score := range checkout.Singles() {
+		headers = append(headers, common.Fill(score.String(), 2))
+	}
+	fieldsTable.Headers(headers...)
+
+	countToCol := map[int]string{}
+	for _, m := range []checkout.Multiplier{checkout.None, checkout.Double, checkout.Triple} {
+		for _, score := range checkout.Singles() {
+			if m == checkout.Triple && score.Value() == checkout.BullsEye {
+				continue
+			}
+			count := score.WithMultiplier(m).String()
+			countToCol[fieldsCount[count]] = ""
+		}
+	}
+
+	common.DistributeColors(string(common.ColorInactive), string(common.ColorGreen), countToCol)
+
+	for _, m := range []checkout.Multiplier{checkout.None, checkout.Double, checkout.Triple} {
+		row := []string{string(m)}
+		for _, score := range checkout.Singles() {
+			if m == checkout.Triple && score.Value() == checkout.BullsEye {
+				row = append(row, "")
+				continue
+			}
+			count := fieldsCount[score.WithMultiplier(m).String()]
+			row = append(row, lipgloss.NewStyle().Foreground(lipgloss.Color(countToCol[count])).Render(strconv.Itoa(count)))
+		}
+		fieldsTable.Row(row...)
+	}
+
+	return fieldsTable
+}
+
 func (s *Model) SetBackTo(cmd tea.Cmd) {
 	s.backTo = cmd
 }
 
 func (s *Model) SetPlayerStats(ps *datastore.PlayerStats) {
 	s.ps = ps
+	s.form = ps.Form
+
+	if s.form == nil {
+		// the precomputed statistics do not contain the form, it is only loaded for the details
+		form, err := s.ds.GetPlayerForm(ps.ID)
+		if err != nil {
+			s.log.Error("unable to load form of player", "id", ps.ID, "error", err)
+		}
+
+		s.form = form
+	}
 }
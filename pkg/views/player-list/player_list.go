@@ -10,8 +10,10 @@ import (
 
 	"github.com/Gerrit91/darts-counter/pkg/datastore"
 	"github.com/Gerrit91/darts-counter/pkg/views/common"
+	headtohead "github.com/Gerrit91/darts-counter/pkg/views/head-to-head"
 	playerdetails "github.com/Gerrit91/darts-counter/pkg/views/player-details"
 
+	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -30,39 +32,60 @@ type (
 		help          help.Model
 		err           error
 		cursor        int
+		sortByRating  bool
 		stats         []*datastore.PlayerStats
+		compareWith   *datastore.PlayerStats
 		playerDetails *playerdetails.Model
+		headToHead    *headtohead.Model
+		filter        *common.InputBar
 	}
 )
 
-func New(log *slog.Logger, ds datastore.Datastore, playerDetails *playerdetails.Model) *model {
-	return &model{
+func New(log *slog.Logger, ds datastore.Datastore, playerDetails *playerdetails.Model, headToHead *headtohead.Model) *model {
+	s := &model{
 		log:           log,
 		ds:            ds,
 		viewport:      viewport.New(0, 20),
 		help:          common.NewHelp(),
 		table:         common.NewTable(),
 		playerDetails: playerDetails,
+		headToHead:    headToHead,
 	}
+
+	s.filter = common.NewFilterBar(func(query string) error {
+		profiles, err := s.ds.ListPlayerProfiles()
+		if err != nil {
+			return err
+		}
+
+		_, err = datastore.ParseFilters(query, profiles, time.Now())
+
+		return err
+	})
+
+	return s
 }
 
 func (s *model) Init() tea.Cmd {
-	gameStats, err := s.ds.ListGameStats()
-	if err != nil {
-		s.err = err
+	s.err = nil
+
+	if err := s.filter.Revalidate(); err != nil {
+		s.stats = nil
+		s.compareWith = nil
+		s.cursor = 0
 		return nil
 	}
 
-	s.stats, err = datastore.ToPlayerStats(gameStats)
+	var err error
+	s.stats, err = s.listPlayerStats()
 	if err != nil {
 		s.err = err
 		return nil
 	}
 
-	sort.SliceStable(s.stats, func(i, j int) bool {
-		return s.stats[i].RanksCount[1] > s.stats[j].RanksCount[1]
-	}) // sort by wins
+	s.sort()
 
+	s.compareWith = nil
 	s.cursor = 0
 	s.viewport.GotoTop()
 
@@ -71,14 +94,87 @@ func (s *model) Init() tea.Cmd {
 	return tea.WindowSize()
 }
 
+// listPlayerStats returns the precomputed player statistics, only with a filter they are computed from the matching games
+func (s *model) listPlayerStats() ([]*datastore.PlayerStats, error) {
+	if s.filter.Query() == "" {
+		return s.ds.ListPlayerStats()
+	}
+
+	profiles, err := s.ds.ListPlayerProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := datastore.ParseFilters(s.filter.Query(), profiles, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	gameStats, err := s.ds.ListGameStats(filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return datastore.ToPlayerStats(gameStats, datastore.ToPlayerNames(profiles))
+}
+
+func (s *model) sort() {
+	sort.SliceStable(s.stats, func(i, j int) bool {
+		if s.sortByRating {
+			return s.stats[i].Rating > s.stats[j].Rating
+		}
+		return s.stats[i].RanksCount[1] > s.stats[j].RanksCount[1]
+	})
+}
+
 func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		common.AdjustViewportResize(&s.viewport, msg, s.cursor, 2, 1)
+		common.AdjustViewportResize(&s.viewport, msg, s.cursor, 2, 2)
+	case cursor.BlinkMsg:
+		_, cmd := s.filter.Update(msg)
+		return s, cmd
 	case tea.KeyMsg:
+		if s.filter.IsEditing() {
+			submitted, cmd := s.filter.Update(msg)
+			if submitted {
+				return s, s.Init()
+			}
+			return s, cmd
+		}
+
 		switch msg.String() {
+		case "/":
+			return s, s.filter.Edit()
 		case "q", "esc":
+			if s.compareWith != nil {
+				s.compareWith = nil
+				return s, nil
+			}
 			return s, common.SwitchViewTo(common.MainMenuView)
+		case "c":
+			if len(s.stats) == 0 {
+				return s, nil
+			}
+
+			selected := s.stats[s.cursor]
+
+			switch {
+			case s.compareWith == nil:
+				s.compareWith = selected
+			case s.compareWith.ID == selected.ID:
+				s.compareWith = nil
+			default:
+				s.headToHead.SetPlayers(s.compareWith.ID, selected.ID)
+				return s, common.SwitchViewTo(common.HeadToHeadView)
+			}
+
+			return s, nil
+		case "s":
+			s.sortByRating = !s.sortByRating
+			s.sort()
+			s.cursor = 0
+			s.viewport.GotoTop()
 		case "down":
 			s.cursor++
 			if s.cursor >= len(s.stats) {
@@ -98,6 +194,10 @@ func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			s.cursor = len(s.stats) - 1
 			s.viewport.GotoBottom()
 		case "enter":
+			if len(s.stats) == 0 {
+				return s, nil
+			}
+
 			ps := s.stats[s.cursor]
 			s.playerDetails.SetPlayerStats(ps)
 			return s, common.SwitchViewTo(common.PlayerDetailsView)
@@ -135,12 +235,15 @@ func (s *model) View() string {
 			}
 
 			return []string{
-				stat.ID,
+				stat.Name,
+				strconv.FormatFloat(stat.Rating, 'f', 0, 64),
 				wins,
 				losses,
 				strconv.Itoa(stat.GamesPlayed),
 				strconv.FormatFloat(stat.AverageRank, 'f', 3, 64),
-				strconv.FormatFloat(stat.AverageScore, 'f', 1, 64),
+				strconv.FormatFloat(stat.ThreeDartAverage, 'f', 1, 64),
+				strconv.FormatFloat(stat.FirstNineAverage, 'f', 1, 64),
+				strconv.FormatFloat(stat.CheckoutPercentage, 'f', 1, 64) + "%",
 				fmt.Sprintf("%d (%s)", stat.HighestScore.Total, strings.Join(stat.HighestScore.Fields, " → ")),
 				favField,
 				stat.AverageDuration.Truncate(time.Millisecond).String(),
@@ -150,11 +253,14 @@ func (s *model) View() string {
 			return []string{
 				"",
 				"Name",
+				"Rating",
 				"Wins",
 				"Losses",
 				"Games",
 				"⌀-Rank",
-				"⌀-Score",
+				"3-Dart ⌀",
+				"First 9 ⌀",
+				"Checkout",
 				"Max Score",
 				"Fav Field",
 				"⌀-Sec/move",
@@ -188,6 +294,9 @@ func (s *model) View() string {
 
 	for i, stat := range s.stats {
 		selection := ""
+		if s.compareWith != nil && s.compareWith.ID == stat.ID {
+			selection = "⇢"
+		}
 		if s.cursor == i {
 			selection = "→"
 		}
@@ -199,8 +308,35 @@ func (s *model) View() string {
 		s.viewport.SetContent(t.Render())
 	}
 
+	sortHelp := "sort by rating"
+	if s.sortByRating {
+		sortHelp = "sort by wins"
+	}
+
+	compareHelp := "compare"
+	if s.compareWith != nil {
+		compareHelp = "compare with " + s.compareWith.Name
+	}
+
 	lines = append(lines, common.Headline("Player Statistics"))
 	lines = append(lines, s.viewport.View())
+	lines = append(lines, s.filter.View())
+
+	if s.filter.IsEditing() {
+		lines = append(lines, s.help.ShortHelpView([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "apply filter"),
+			),
+			key.NewBinding(
+				key.WithKeys("esc"),
+				key.WithHelp("esc", "cancel"),
+			),
+		}))
+
+		return strings.Join(lines, "\n")
+	}
+
 	lines = append(lines, s.help.ShortHelpView([]key.Binding{
 		key.NewBinding(
 			key.WithKeys("up"),
@@ -218,6 +354,18 @@ func (s *model) View() string {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "show details"),
 		),
+		key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", sortHelp),
+		),
+		key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", compareHelp),
+		),
+		key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
 		key.NewBinding(
 			key.WithKeys("q", "esc"),
 			key.WithHelp("q", "quit"),
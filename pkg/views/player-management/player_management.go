@@ -0,0 +1,313 @@
+package playermanagement
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gerrit91/darts-counter/pkg/datastore"
+	"github.com/Gerrit91/darts-counter/pkg/views/common"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type (
+	model struct {
+		log *slog.Logger
+		ds  datastore.Datastore
+
+		profiles  []*datastore.PlayerProfile
+		games     map[string]int
+		cursor    int
+		mergeFrom *datastore.PlayerProfile
+		mergeInto *datastore.PlayerProfile
+		editing   editField
+		msg       string
+
+		viewport  viewport.Model
+		textInput textinput.Model
+		help      help.Model
+		err       error
+	}
+
+	editField string
+
+	mergePlayersMsg struct{}
+)
+
+const (
+	editName     editField = "name"
+	editNickname editField = "nickname"
+)
+
+func MergePlayers() tea.Msg {
+	return mergePlayersMsg{}
+}
+
+func New(log *slog.Logger, ds datastore.Datastore) *model {
+	return &model{
+		log:       log,
+		ds:        ds,
+		viewport:  viewport.New(0, 20),
+		textInput: common.NewTextInput(),
+		help:      common.NewHelp(),
+	}
+}
+
+func (s *model) Init() tea.Cmd {
+	var err error
+	s.profiles, err = s.ds.ListPlayerProfiles()
+	if err != nil {
+		s.err = err
+		return nil
+	}
+
+	s.games, err = s.ds.CountGamesByPlayer()
+	if err != nil {
+		s.err = err
+		return nil
+	}
+
+	if s.cursor >= len(s.profiles) {
+		s.cursor = 0
+		s.viewport.GotoTop()
+	}
+
+	s.log.Info("fetched player profiles from database", "entries", len(s.profiles))
+
+	return tea.Batch(tea.WindowSize(), s.textInput.Cursor.BlinkCmd())
+}
+
+func (s *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case mergePlayersMsg:
+		if s.mergeFrom == nil || s.mergeInto == nil {
+			s.log.Error("no players marked for merging")
+			return s, s.Init()
+		}
+
+		s.log.Info("merging players", "from", s.mergeFrom.ID, "into", s.mergeInto.ID)
+
+		err := s.ds.MergePlayerProfiles(s.mergeFrom.ID, s.mergeInto.ID)
+		if err != nil {
+			s.err = err
+		} else {
+			s.msg = fmt.Sprintf("merged %s into %s", s.mergeFrom.Name, s.mergeInto.Name)
+		}
+
+		s.mergeFrom = nil
+		s.mergeInto = nil
+
+		return s, s.Init()
+	case tea.WindowSizeMsg:
+		common.AdjustViewportResize(&s.viewport, msg, s.cursor, 2, 3)
+	case cursor.BlinkMsg:
+		var cmd tea.Cmd
+		s.textInput, cmd = s.textInput.Update(msg)
+		return s, cmd
+	case tea.KeyMsg:
+		s.err = nil
+		s.msg = ""
+
+		if s.editing != "" {
+			switch msg.String() {
+			case "esc":
+				s.editing = ""
+				s.textInput.Reset()
+				return s, nil
+			case "enter":
+				updated := *s.profiles[s.cursor]
+				switch s.editing {
+				case editName:
+					updated.Name = strings.TrimSpace(s.textInput.Value())
+				case editNickname:
+					updated.Nickname = strings.TrimSpace(s.textInput.Value())
+				}
+
+				err := s.ds.UpdatePlayerProfile(&updated)
+				if err != nil {
+					s.err = err
+					return s, nil
+				}
+
+				s.editing = ""
+				s.textInput.Reset()
+
+				return s, s.Init()
+			}
+
+			var cmd tea.Cmd
+			s.textInput, cmd = s.textInput.Update(msg)
+
+			return s, cmd
+		}
+
+		if len(s.profiles) == 0 {
+			switch msg.String() {
+			case "q", "esc":
+				return s, common.SwitchViewTo(common.MainMenuView)
+			}
+			return s, nil
+		}
+
+		switch msg.String() {
+		case "q", "esc":
+			if s.mergeFrom != nil {
+				s.mergeFrom = nil
+				return s, nil
+			}
+			return s, common.SwitchViewTo(common.MainMenuView)
+		case "r":
+			s.editing = editName
+			s.textInput.SetValue(s.profiles[s.cursor].Name)
+		case "n":
+			s.editing = editNickname
+			s.textInput.SetValue(s.profiles[s.cursor].Nickname)
+		case "m":
+			selected := s.profiles[s.cursor]
+
+			if s.mergeFrom == nil {
+				s.mergeFrom = selected
+				return s, nil
+			}
+
+			if s.mergeFrom.ID == selected.ID {
+				s.err = fmt.Errorf("select another player to merge %s into", selected.Name)
+				return s, nil
+			}
+
+			s.mergeInto = selected
+
+			return s, common.SwitchViewTo(common.MergePlayersView)
+		case "down":
+			s.cursor++
+			if s.cursor >= len(s.profiles) {
+				s.cursor = 0
+				s.viewport.GotoTop()
+			}
+		case "up":
+			s.cursor--
+			if s.cursor < 0 {
+				s.cursor = len(s.profiles) - 1
+				s.viewport.GotoBottom()
+			}
+		case "g":
+			s.cursor = 0
+			s.viewport.GotoTop()
+		case "G":
+			s.cursor = len(s.profiles) - 1
+			s.viewport.GotoBottom()
+		}
+	}
+
+	var cmd tea.Cmd
+	s.viewport, cmd = s.viewport.Update(msg)
+
+	return s, cmd
+}
+
+func (s *model) View() string {
+	var lines []string
+
+	lines = append(lines, common.Headline("Manage Players"))
+
+	t := common.NewTable().StyleFunc(func(row, col int) lipgloss.Style {
+		switch {
+		case col == 0:
+			return common.StylePink
+		case row == s.cursor:
+			return common.StyleActive
+		default:
+			return common.StyleInactive
+		}
+	})
+
+	t.Headers("", "Name", "Nickname", "Games", "Created")
+
+	for i, p := range s.profiles {
+		selection := ""
+		if s.mergeFrom != nil && s.mergeFrom.ID == p.ID {
+			selection = "⇢"
+		}
+		if s.cursor == i {
+			selection = "→"
+		}
+
+		t = t.Row(selection, p.Name, p.Nickname, strconv.Itoa(s.games[p.ID]), p.Created.Format(time.DateOnly))
+	}
+
+	if s.viewport.Height > 0 { // otherwise it crashes
+		s.viewport.SetContent(t.Render())
+	}
+
+	lines = append(lines, s.viewport.View())
+
+	switch {
+	case s.err != nil:
+		lines = append(lines, common.StyleError.Render(s.err.Error()))
+	case s.msg != "":
+		lines = append(lines, s.msg)
+	case s.mergeFrom != nil:
+		lines = append(lines, fmt.Sprintf("Select the player to merge %s into.", s.mergeFrom.Name))
+	default:
+		lines = append(lines, "")
+	}
+
+	if s.editing != "" {
+		lines = append(lines, fmt.Sprintf("Enter %s:", s.editing), s.textInput.View())
+		lines = append(lines, s.help.ShortHelpView([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "save"),
+			),
+			key.NewBinding(
+				key.WithKeys("esc"),
+				key.WithHelp("esc", "cancel"),
+			),
+		}))
+
+		return strings.Join(lines, "\n")
+	}
+
+	mergeHelp := "merge"
+	if s.mergeFrom != nil {
+		mergeHelp = "merge into"
+	}
+
+	lines = append(lines, s.help.ShortHelpView([]key.Binding{
+		key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "up"),
+		),
+		key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "down"),
+		),
+		key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "rename"),
+		),
+		key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "nickname"),
+		),
+		key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", mergeHelp),
+		),
+		key.NewBinding(
+			key.WithKeys("q", "esc"),
+			key.WithHelp("q", "quit"),
+		),
+	}))
+
+	return strings.Join(lines, "\n")
+}